@@ -1,6 +1,7 @@
 package stream_chat //nolint: golint
 
 import (
+	"context"
 	"log"
 	"testing"
 
@@ -8,25 +9,31 @@ import (
 )
 
 func TestClient_GetApp(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
-	_, err := c.GetAppConfig()
+	_, err := c.GetAppConfig(ctx)
 	require.NoError(t, err)
 }
 
 func TestClient_UpdateAppSettings(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 
 	settings := NewAppSettings().
 		SetDisableAuth(true).
 		SetDisablePermissions(true)
 
-	err := c.UpdateAppSettings(settings)
+	err := c.UpdateAppSettings(ctx, settings)
 	require.NoError(t, err)
 }
 
 // See https://getstream.io/chat/docs/app_settings_auth/ for
 // more details.
 func ExampleClient_UpdateAppSettings_disable_auth() {
+	ctx := context.Background()
+
 	client, err := NewClient("XXXXXXXXXXXX", "XXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXX")
 	if err != nil {
 		log.Fatalf("Err: %v", err)
@@ -34,19 +41,21 @@ func ExampleClient_UpdateAppSettings_disable_auth() {
 
 	// disable auth checks, allows dev token usage
 	settings := NewAppSettings().SetDisableAuth(true)
-	err = client.UpdateAppSettings(settings)
+	err = client.UpdateAppSettings(ctx, settings)
 	if err != nil {
 		log.Fatalf("Err: %v", err)
 	}
 
 	// re-enable auth checks
-	err = client.UpdateAppSettings(NewAppSettings().SetDisableAuth(false))
+	err = client.UpdateAppSettings(ctx, NewAppSettings().SetDisableAuth(false))
 	if err != nil {
 		log.Fatalf("Err: %v", err)
 	}
 }
 
 func ExampleClient_UpdateAppSettings_disable_permission() {
+	ctx := context.Background()
+
 	client, err := NewClient("XXXX", "XXXX")
 	if err != nil {
 		log.Fatalf("Err: %v", err)
@@ -54,13 +63,13 @@ func ExampleClient_UpdateAppSettings_disable_permission() {
 
 	// disable permission checkse
 	settings := NewAppSettings().SetDisablePermissions(true)
-	err = client.UpdateAppSettings(settings)
+	err = client.UpdateAppSettings(ctx, settings)
 	if err != nil {
 		log.Fatalf("Err: %v", err)
 	}
 
 	// re-enable permission checks
-	err = client.UpdateAppSettings(NewAppSettings().SetDisablePermissions(false))
+	err = client.UpdateAppSettings(ctx, NewAppSettings().SetDisablePermissions(false))
 	if err != nil {
 		log.Fatalf("Err: %v", err)
 	}
@@ -1,6 +1,7 @@
 package stream_chat //nolint: golint
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -29,7 +30,7 @@ type Task struct {
 }
 
 // GetTask returns the status of a task that has been ran asynchronously.
-func (c *Client) GetTask(id string) (*Task, error) {
+func (c *Client) GetTask(ctx context.Context, id string) (*Task, error) {
 	if id == "" {
 		return nil, fmt.Errorf("id should not be empty")
 	}
@@ -37,9 +38,9 @@ func (c *Client) GetTask(id string) (*Task, error) {
 	p := path.Join("tasks", url.PathEscape(id))
 
 	var task Task
-	err := c.makeRequest(http.MethodGet, p, nil, nil, &task)
+	err := c.makeRequest(ctx, http.MethodGet, p, nil, nil, &task)
 	if err != nil {
-		return nil, fmt.Errorf("cannot get task status: %v", err)
+		return nil, fmt.Errorf("cannot get task status: %w", err)
 	}
 
 	return &task, nil
@@ -52,7 +53,7 @@ type AsyncTaskResponse struct {
 // DeleteChannels deletes channels asynchronously.
 // Channels and messages will be hard deleted if hardDelete is true.
 // It returns a task ID, the status of the task can be check with client.GetTask method.
-func (c *Client) DeleteChannels(cids []string, hardDelete bool) (string, error) {
+func (c *Client) DeleteChannels(ctx context.Context, cids []string, hardDelete bool) (string, error) {
 	if len(cids) == 0 {
 		return "", fmt.Errorf("cids parameter should not be empty")
 	}
@@ -66,9 +67,9 @@ func (c *Client) DeleteChannels(cids []string, hardDelete bool) (string, error)
 	}
 
 	var resp AsyncTaskResponse
-	err := c.makeRequest(http.MethodPost, "channels/delete", nil, data, &resp)
+	err := c.makeRequest(ctx, http.MethodPost, "channels/delete", nil, data, &resp)
 	if err != nil {
-		return "", fmt.Errorf("cannot delete channels: %v", err)
+		return "", fmt.Errorf("cannot delete channels: %w", err)
 	}
 
 	return resp.TaskID, nil
@@ -94,7 +95,7 @@ type DeleteUserOptions struct {
 // Messages will be deleted if either "hard" or "soft"
 // NewChannelOwnerID any channels owned by the hard-deleted user will be transferred to this user ID
 // It returns a task ID, the status of the task can be check with client.GetTask method.
-func (c *Client) DeleteUsers(userIDs []string, options DeleteUserOptions) (string, error) {
+func (c *Client) DeleteUsers(ctx context.Context, userIDs []string, options DeleteUserOptions) (string, error) {
 	if len(userIDs) == 0 {
 		return "", fmt.Errorf("userIDs parameter should not be empty")
 	}
@@ -108,9 +109,9 @@ func (c *Client) DeleteUsers(userIDs []string, options DeleteUserOptions) (strin
 	}
 
 	var resp AsyncTaskResponse
-	err := c.makeRequest(http.MethodPost, "users/delete", nil, data, &resp)
+	err := c.makeRequest(ctx, http.MethodPost, "users/delete", nil, data, &resp)
 	if err != nil {
-		return "", fmt.Errorf("cannot delete users: %v", err)
+		return "", fmt.Errorf("cannot delete users: %w", err)
 	}
 
 	return resp.TaskID, nil
@@ -125,7 +126,7 @@ type ExportableChannel struct {
 
 // ExportChannels requests an asynchronous export of the provided channels and returns
 // the ID of task.
-func (c *Client) ExportChannels(channels []*ExportableChannel, clearDeletedMessageText, includeTruncatedMessages *bool) (string, error) {
+func (c *Client) ExportChannels(ctx context.Context, channels []*ExportableChannel, clearDeletedMessageText, includeTruncatedMessages *bool) (string, error) {
 	if len(channels) == 0 {
 		return "", errors.New("number of channels must be at least one")
 	}
@@ -146,7 +147,7 @@ func (c *Client) ExportChannels(channels []*ExportableChannel, clearDeletedMessa
 	}
 
 	var resp AsyncTaskResponse
-	if err := c.makeRequest(http.MethodPost, "export_channels", nil, req, &resp); err != nil {
+	if err := c.makeRequest(ctx, http.MethodPost, "export_channels", nil, req, &resp); err != nil {
 		return "", err
 	}
 
@@ -163,7 +164,7 @@ func verifyExportableChannels(channels []*ExportableChannel) error {
 }
 
 // GetExportChannelsTask returns current state of the export task.
-func (c *Client) GetExportChannelsTask(taskID string) (*Task, error) {
+func (c *Client) GetExportChannelsTask(ctx context.Context, taskID string) (*Task, error) {
 	task := &Task{}
 
 	if taskID == "" {
@@ -172,6 +173,112 @@ func (c *Client) GetExportChannelsTask(taskID string) (*Task, error) {
 
 	p := path.Join("export_channels", url.PathEscape(taskID))
 
-	err := c.makeRequest(http.MethodGet, p, nil, nil, task)
+	err := c.makeRequest(ctx, http.MethodGet, p, nil, nil, task)
 	return task, err
 }
+
+// ErrTaskFailed matches the TaskFailedError returned by WaitForTask, with errors.Is.
+var ErrTaskFailed = errors.New("chat-client: task failed")
+
+// TaskFailedError is returned by WaitForTask when the task failed.
+type TaskFailedError struct {
+	// Task is the failed task, its Result describes the failure.
+	Task *Task
+}
+
+// Error implements error.
+func (e *TaskFailedError) Error() string {
+	return fmt.Sprintf("%v: %s, result: %v", ErrTaskFailed, e.Task.TaskID, e.Task.Result)
+}
+
+// Is makes errors.Is(err, ErrTaskFailed) true.
+func (e *TaskFailedError) Is(target error) bool {
+	return target == ErrTaskFailed
+}
+
+const (
+	defaultTaskPollInterval    = 500 * time.Millisecond
+	defaultMaxTaskPollInterval = 5 * time.Second
+)
+
+// WaitOption configures WaitForTask.
+type WaitOption func(*waitOptions)
+
+type waitOptions struct {
+	interval    time.Duration
+	maxInterval time.Duration
+	exportTask  bool
+	progress    func(*Task)
+}
+
+// WithPollInterval sets the delay before polling the task again, doubled after every poll up
+// to max. By default, it starts at 500ms and goes up to 5s.
+func WithPollInterval(initial, max time.Duration) WaitOption {
+	return func(o *waitOptions) {
+		o.interval = initial
+		o.maxInterval = max
+	}
+}
+
+// WithTaskProgress sets a function called with the task when its status changes,
+// starting with the first status seen.
+func WithTaskProgress(progress func(*Task)) WaitOption {
+	return func(o *waitOptions) {
+		o.progress = progress
+	}
+}
+
+// WithExportChannelsTask polls the task with GetExportChannelsTask, for the tasks created by
+// ExportChannels.
+func WithExportChannelsTask() WaitOption {
+	return func(o *waitOptions) {
+		o.exportTask = true
+	}
+}
+
+// WaitForTask polls the task until it completes or the context is done, and returns the
+// completed task. If the task fails, it is returned with a *TaskFailedError.
+func (c *Client) WaitForTask(ctx context.Context, taskID string, options ...WaitOption) (*Task, error) {
+	o := waitOptions{interval: defaultTaskPollInterval, maxInterval: defaultMaxTaskPollInterval}
+	for _, opt := range options {
+		opt(&o)
+	}
+
+	get := c.GetTask
+	if o.exportTask {
+		get = c.GetExportChannelsTask
+	}
+
+	var status TaskStatus
+	interval := o.interval
+	if interval <= 0 {
+		interval = defaultTaskPollInterval
+	}
+	for {
+		task, err := get(ctx, taskID)
+		if err != nil {
+			return nil, err
+		}
+
+		if task.Status != status {
+			status = task.Status
+			if o.progress != nil {
+				o.progress(task)
+			}
+		}
+
+		switch task.Status {
+		case TaskStatusCompleted:
+			return task, nil
+		case TaskStatusFailed:
+			return task, &TaskFailedError{Task: task}
+		}
+
+		if err := sleepContext(ctx, interval); err != nil {
+			return nil, err
+		}
+		if interval *= 2; o.maxInterval > 0 && interval > o.maxInterval {
+			interval = o.maxInterval
+		}
+	}
+}
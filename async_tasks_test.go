@@ -1,6 +1,12 @@
 package stream_chat //nolint: golint
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -8,25 +14,27 @@ import (
 )
 
 func TestClient_DeleteChannels(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 	ch := initChannel(t, c)
 
 	user := randomUser(t, c)
 	msg := &Message{Text: "test message"}
 
-	_, err := ch.SendMessage(msg, user.ID, MessageSkipPush)
+	_, err := ch.SendMessage(ctx, msg, user.ID, MessageSkipPush)
 	require.NoError(t, err, "send message")
 
 	// should fail without CIDs in parameter
-	_, err = c.DeleteChannels([]string{}, true)
+	_, err = c.DeleteChannels(ctx, []string{}, true)
 	require.Error(t, err)
 
-	taskID, err := c.DeleteChannels([]string{ch.CID}, true)
+	taskID, err := c.DeleteChannels(ctx, []string{ch.CID}, true)
 	require.NoError(t, err)
 	require.NotEmpty(t, taskID)
 
 	for i := 0; i < 10; i++ {
-		resp, err := c.GetTask(taskID)
+		resp, err := c.GetTask(ctx, taskID)
 		require.NoError(t, err)
 		require.Equal(t, taskID, resp.TaskID)
 
@@ -40,6 +48,8 @@ func TestClient_DeleteChannels(t *testing.T) {
 }
 
 func TestClient_DeleteUsers(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 	ch := initChannel(t, c)
 
@@ -47,17 +57,17 @@ func TestClient_DeleteUsers(t *testing.T) {
 
 	msg := &Message{Text: "test message"}
 
-	_, err := ch.SendMessage(msg, user.ID, MessageSkipPush)
+	_, err := ch.SendMessage(ctx, msg, user.ID, MessageSkipPush)
 	require.NoError(t, err, "send message")
 
 	// should fail without userIDs in parameter
-	_, err = c.DeleteUsers([]string{}, DeleteUserOptions{
+	_, err = c.DeleteUsers(ctx, []string{}, DeleteUserOptions{
 		User:     SoftDelete,
 		Messages: HardDelete,
 	})
 	require.Error(t, err)
 
-	taskID, err := c.DeleteUsers([]string{user.ID}, DeleteUserOptions{
+	taskID, err := c.DeleteUsers(ctx, []string{user.ID}, DeleteUserOptions{
 		User:     SoftDelete,
 		Messages: HardDelete,
 	})
@@ -65,7 +75,7 @@ func TestClient_DeleteUsers(t *testing.T) {
 	require.NotEmpty(t, taskID)
 
 	for i := 0; i < 10; i++ {
-		resp, err := c.GetTask(taskID)
+		resp, err := c.GetTask(ctx, taskID)
 		require.NoError(t, err)
 		require.Equal(t, taskID, resp.TaskID)
 
@@ -81,6 +91,8 @@ func TestClient_DeleteUsers(t *testing.T) {
 }
 
 func TestClient_ExportChannels(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 	ch1 := initChannel(t, c)
 	ch2 := initChannel(t, c)
@@ -96,12 +108,12 @@ func TestClient_ExportChannels(t *testing.T) {
 		}
 
 		for _, u := range chMembers {
-			_ = c.DeleteUser(u.UserID, options)
+			_ = c.DeleteUser(ctx, u.UserID, options)
 		}
 	}()
 
 	t.Run("Return error if there are 0 channels", func(t *testing.T) {
-		_, err := c.ExportChannels(nil, nil, nil)
+		_, err := c.ExportChannels(ctx, nil, nil, nil)
 		require.Error(t, err)
 	})
 
@@ -109,7 +121,7 @@ func TestClient_ExportChannels(t *testing.T) {
 		expChannels := []*ExportableChannel{
 			{Type: "", ID: ch1.ID},
 		}
-		_, err := c.ExportChannels(expChannels, nil, nil)
+		_, err := c.ExportChannels(ctx, expChannels, nil, nil)
 		require.Error(t, err)
 	})
 
@@ -119,12 +131,12 @@ func TestClient_ExportChannels(t *testing.T) {
 			{Type: ch2.Type, ID: ch2.ID},
 		}
 
-		taskID, err := c.ExportChannels(expChannels, nil, nil)
+		taskID, err := c.ExportChannels(ctx, expChannels, nil, nil)
 		require.NoError(t, err)
 		require.NotEmpty(t, taskID)
 
 		for i := 0; i < 10; i++ {
-			task, err := c.GetExportChannelsTask(taskID)
+			task, err := c.GetExportChannelsTask(ctx, taskID)
 			require.NoError(t, err)
 			require.Equal(t, taskID, task.TaskID)
 			require.NotEmpty(t, task.Status)
@@ -137,3 +149,60 @@ func TestClient_ExportChannels(t *testing.T) {
 		}
 	})
 }
+
+func TestClient_WaitForTask(t *testing.T) {
+	ctx := context.Background()
+
+	c := initClient(t)
+	ch := initChannel(t, c)
+
+	taskID, err := c.DeleteChannels(ctx, []string{ch.CID}, true)
+	require.NoError(t, err)
+
+	var statuses []TaskStatus
+	task, err := c.WaitForTask(ctx, taskID, WithPollInterval(100*time.Millisecond, time.Second),
+		WithTaskProgress(func(task *Task) { statuses = append(statuses, task.Status) }))
+	require.NoError(t, err)
+	require.Equal(t, TaskStatusCompleted, task.Status)
+	require.Equal(t, map[string]interface{}{"status": "ok"}, task.Result[ch.CID])
+	require.Equal(t, TaskStatusCompleted, statuses[len(statuses)-1])
+}
+
+func TestClient_WaitForTaskFailed(t *testing.T) {
+	var polls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls = append(polls, r.URL.Path)
+		status := TaskStatusRunning
+		switch len(polls) {
+		case 1:
+			status = TaskStatusWaiting
+		case 4:
+			if strings.HasPrefix(r.URL.Path, "/export_channels/") {
+				status = TaskStatusFailed
+			}
+		}
+		_ = json.NewEncoder(w).Encode(Task{TaskID: "task", Status: status, Result: map[string]interface{}{"error": "boom"}})
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("key", "secret", WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	var statuses []TaskStatus
+	task, err := c.WaitForTask(context.Background(), "task", WithExportChannelsTask(),
+		WithPollInterval(time.Millisecond, 2*time.Millisecond),
+		WithTaskProgress(func(task *Task) { statuses = append(statuses, task.Status) }))
+	require.True(t, errors.Is(err, ErrTaskFailed))
+	var failed *TaskFailedError
+	require.True(t, errors.As(err, &failed))
+	require.Equal(t, "boom", failed.Task.Result["error"])
+	require.Equal(t, failed.Task, task)
+	require.Equal(t, []TaskStatus{TaskStatusWaiting, TaskStatusRunning, TaskStatusFailed}, statuses,
+		"the progress is reported on status changes")
+	require.Equal(t, []string{"/export_channels/task", "/export_channels/task", "/export_channels/task", "/export_channels/task"}, polls)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = c.WaitForTask(ctx, "task", WithPollInterval(time.Millisecond, time.Millisecond))
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+}
@@ -0,0 +1,232 @@
+package stream_chat // nolint: golint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+const (
+	// maxBulkChunkSize is the largest number of items accepted by the batch endpoints in a single request.
+	maxBulkChunkSize = 100
+	// defaultBulkConcurrency is the default number of chunks sent at the same time.
+	defaultBulkConcurrency = 4
+)
+
+type bulkOptions struct {
+	chunkSize   int
+	concurrency int
+}
+
+// BulkOption configures the Bulk* methods.
+type BulkOption func(*bulkOptions)
+
+// WithChunkSize sets the number of items sent in a single request, at most the server limit of 100.
+func WithChunkSize(size int) BulkOption {
+	return func(o *bulkOptions) {
+		o.chunkSize = size
+	}
+}
+
+// WithConcurrency sets the maximum number of requests sent at the same time, 4 by default.
+func WithConcurrency(n int) BulkOption {
+	return func(o *bulkOptions) {
+		o.concurrency = n
+	}
+}
+
+func newBulkOptions(options []BulkOption) bulkOptions {
+	o := bulkOptions{chunkSize: maxBulkChunkSize, concurrency: defaultBulkConcurrency}
+	for _, opt := range options {
+		opt(&o)
+	}
+	if o.chunkSize <= 0 || o.chunkSize > maxBulkChunkSize {
+		o.chunkSize = maxBulkChunkSize
+	}
+	if o.concurrency <= 0 {
+		o.concurrency = defaultBulkConcurrency
+	}
+	return o
+}
+
+// BulkError is returned by the Bulk* methods when some of the items could not be processed.
+// The other items were processed successfully and their results are returned with the error.
+type BulkError struct {
+	// Errors holds the error of every failed item, keyed by user ID or channel CID.
+	// The items sent in the same request share the error of the request.
+	Errors map[string]error
+}
+
+// Error implements error.
+func (e *BulkError) Error() string {
+	if len(e.Errors) == 0 {
+		return "chat-client: bulk operation failed"
+	}
+
+	ids := make([]string, 0, len(e.Errors))
+	for id := range e.Errors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return fmt.Sprintf("chat-client: %d items failed, first %s: %v", len(ids), ids[0], e.Errors[ids[0]])
+}
+
+// runChunks calls send for every chunk of the items identified by ids, running up to
+// o.concurrency calls at the same time. It returns a BulkError with the items of the failed
+// chunks, or nil if all of them succeeded.
+func runChunks(ctx context.Context, ids []string, o bulkOptions, send func(ctx context.Context, start, end int) error) error {
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs = make(map[string]error)
+		sem  = make(chan struct{}, o.concurrency)
+	)
+	fail := func(start, end int, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, id := range ids[start:end] {
+			errs[id] = err
+		}
+	}
+
+	for start := 0; start < len(ids); start += o.chunkSize {
+		end := start + o.chunkSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			fail(start, end, ctx.Err())
+			continue
+		}
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := send(ctx, start, end); err != nil {
+				fail(start, end, err)
+			}
+		}(start, end)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return &BulkError{Errors: errs}
+	}
+	return nil
+}
+
+// BulkUpsertUsers is like UpsertUsers for any number of users: they are split in chunks sent
+// concurrently and the upserted users are merged. If some chunks fail, the users of the other
+// chunks are returned with a *BulkError.
+func (c *Client) BulkUpsertUsers(ctx context.Context, users []*User, options ...BulkOption) (map[string]*User, error) {
+	if len(users) == 0 {
+		return nil, errors.New("users are not set")
+	}
+
+	ids := make([]string, len(users))
+	for i, u := range users {
+		if u == nil {
+			return nil, fmt.Errorf("user %d is nil", i)
+		}
+		ids[i] = u.ID
+	}
+
+	var mu sync.Mutex
+	result := make(map[string]*User, len(users))
+	err := runChunks(ctx, ids, newBulkOptions(options), func(ctx context.Context, start, end int) error {
+		upserted, err := c.UpsertUsers(ctx, users[start:end]...)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		for id, u := range upserted {
+			result[id] = u
+		}
+		return nil
+	})
+	return result, err
+}
+
+// BulkPartialUpdateUsers is like PartialUpdateUsers for any number of updates: they are split in
+// chunks sent concurrently and the updated users are merged. If some chunks fail, the users of
+// the other chunks are returned with a *BulkError.
+func (c *Client) BulkPartialUpdateUsers(ctx context.Context, updates []PartialUserUpdate, options ...BulkOption) (map[string]*User, error) {
+	if len(updates) == 0 {
+		return nil, errors.New("updates should not be empty")
+	}
+
+	ids := make([]string, len(updates))
+	for i, u := range updates {
+		ids[i] = u.ID
+	}
+
+	var mu sync.Mutex
+	result := make(map[string]*User, len(updates))
+	err := runChunks(ctx, ids, newBulkOptions(options), func(ctx context.Context, start, end int) error {
+		updated, err := c.PartialUpdateUsers(ctx, updates[start:end])
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		for id, u := range updated {
+			result[id] = u
+		}
+		return nil
+	})
+	return result, err
+}
+
+// BulkDeleteUsers is like DeleteUsers for any number of users: they are split in chunks sent
+// concurrently, each one deleted by its own task. It returns the ID of the task of every chunk,
+// in the order of the chunks. If some chunks fail, their task IDs are empty and a *BulkError is
+// returned.
+func (c *Client) BulkDeleteUsers(ctx context.Context, userIDs []string, deleteOptions DeleteUserOptions, options ...BulkOption) ([]string, error) {
+	if len(userIDs) == 0 {
+		return nil, errors.New("userIDs parameter should not be empty")
+	}
+
+	return runTaskChunks(ctx, userIDs, newBulkOptions(options), func(ctx context.Context, ids []string) (string, error) {
+		return c.DeleteUsers(ctx, ids, deleteOptions)
+	})
+}
+
+// BulkDeleteChannels is like DeleteChannels for any number of channels: they are split in chunks
+// sent concurrently, each one deleted by its own task. It returns the ID of the task of every
+// chunk, in the order of the chunks. If some chunks fail, their task IDs are empty and a
+// *BulkError is returned.
+func (c *Client) BulkDeleteChannels(ctx context.Context, cids []string, hardDelete bool, options ...BulkOption) ([]string, error) {
+	if len(cids) == 0 {
+		return nil, errors.New("cids parameter should not be empty")
+	}
+
+	return runTaskChunks(ctx, cids, newBulkOptions(options), func(ctx context.Context, ids []string) (string, error) {
+		return c.DeleteChannels(ctx, ids, hardDelete)
+	})
+}
+
+// runTaskChunks runs the chunks of an asynchronous endpoint and collects the task IDs by chunk:
+// the task of ids[i] is at index i/o.chunkSize, empty if its chunk failed.
+func runTaskChunks(ctx context.Context, ids []string, o bulkOptions, send func(ctx context.Context, ids []string) (string, error)) ([]string, error) {
+	taskIDs := make([]string, (len(ids)+o.chunkSize-1)/o.chunkSize)
+	err := runChunks(ctx, ids, o, func(ctx context.Context, start, end int) error {
+		taskID, err := send(ctx, ids[start:end])
+		if err != nil {
+			return err
+		}
+		taskIDs[start/o.chunkSize] = taskID
+		return nil
+	})
+	return taskIDs, err
+}
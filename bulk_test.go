@@ -0,0 +1,138 @@
+package stream_chat // nolint: golint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_BulkUpsertUsers(t *testing.T) {
+	ctx := context.Background()
+	c := initClient(t)
+
+	users := make([]*User, 250)
+	for i := range users {
+		users[i] = &User{ID: randomString(12)}
+	}
+
+	got, err := c.BulkUpsertUsers(ctx, users, WithChunkSize(100))
+	require.NoError(t, err)
+	require.Len(t, got, len(users))
+	for _, u := range users {
+		require.Contains(t, got, u.ID)
+	}
+
+	updates := make([]PartialUserUpdate, len(users))
+	for i, u := range users {
+		updates[i] = PartialUserUpdate{ID: u.ID, Set: map[string]interface{}{"color": "blue"}}
+	}
+	got, err = c.BulkPartialUpdateUsers(ctx, updates)
+	require.NoError(t, err)
+	require.Len(t, got, len(users))
+	require.Equal(t, "blue", got[users[0].ID].ExtraData["color"])
+
+	ids := make([]string, len(users))
+	for i, u := range users {
+		ids[i] = u.ID
+	}
+	taskIDs, err := c.BulkDeleteUsers(ctx, ids, DeleteUserOptions{User: HardDelete})
+	require.NoError(t, err)
+	require.Len(t, taskIDs, 3)
+}
+
+func TestClient_BulkPartialFailure(t *testing.T) {
+	ctx := context.Background()
+
+	var inFlight, maxInFlight int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		var req struct {
+			Users map[string]interface{} `json:"users"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if _, ok := req.Users["bad"]; ok {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":4,"message":"invalid user"}`))
+			return
+		}
+
+		resp := usersResponse{Users: make(map[string]*User)}
+		for id := range req.Users {
+			resp.Users[id] = &User{ID: id}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("key", "secret", WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	users := []*User{{ID: "a"}, {ID: "b"}, {ID: "bad"}, {ID: "c"}, {ID: "d"}, {ID: "e"}, {ID: "f"}}
+	got, err := c.BulkUpsertUsers(ctx, users, WithChunkSize(2), WithConcurrency(2))
+	require.Error(t, err)
+
+	var bulkErr *BulkError
+	require.True(t, errors.As(err, &bulkErr))
+	require.Len(t, bulkErr.Errors, 2, "the users sent with the failed user fail too")
+	require.True(t, IsInputError(bulkErr.Errors["bad"]))
+	require.Contains(t, bulkErr.Errors, "c")
+
+	require.Len(t, got, 5)
+	require.NotContains(t, got, "bad")
+	require.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(2))
+}
+
+func TestClient_BulkDeletePartialFailure(t *testing.T) {
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			UserIDs []string `json:"user_ids"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.UserIDs[0] == "c" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":4,"message":"invalid user"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"task_id":"task-` + req.UserIDs[0] + `"}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("key", "secret", WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	taskIDs, err := c.BulkDeleteUsers(ctx, []string{"a", "b", "c", "d", "e"}, DeleteUserOptions{}, WithChunkSize(2))
+	var bulkErr *BulkError
+	require.True(t, errors.As(err, &bulkErr))
+	require.Equal(t, []string{"task-a", "", "task-e"}, taskIDs, "the failed chunk keeps its position")
+	require.Len(t, bulkErr.Errors, 2)
+}
+
+func TestClient_BulkUpsertNilUser(t *testing.T) {
+	c, err := NewClient("key", "secret")
+	require.NoError(t, err)
+
+	_, err = c.BulkUpsertUsers(context.Background(), []*User{{ID: "a"}, nil})
+	require.EqualError(t, err, "user 1 is nil")
+}
+
+func TestBulkError_Empty(t *testing.T) {
+	require.Equal(t, "chat-client: bulk operation failed", (&BulkError{}).Error())
+}
@@ -1,6 +1,7 @@
 package stream_chat // nolint: golint
 
 import (
+	"context"
 	"log"
 	"os"
 	"path"
@@ -12,6 +13,8 @@ import (
 )
 
 func TestClient_CreateChannel(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 
 	userID := randomUser(t, c).ID
@@ -19,7 +22,7 @@ func TestClient_CreateChannel(t *testing.T) {
 	t.Run("get existing channel", func(t *testing.T) {
 		membersID := randomUsersID(t, c, 3)
 		ch := initChannel(t, c, membersID...)
-		got, err := c.CreateChannel(ch.Type, ch.ID, userID, nil)
+		got, err := c.CreateChannel(ctx, ch.Type, ch.ID, userID, nil)
 		require.NoError(t, err, "create channel", ch)
 
 		assert.Equal(t, c, got.client, "client link")
@@ -49,7 +52,7 @@ func TestClient_CreateChannel(t *testing.T) {
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := c.CreateChannel(tt.channelType, tt.id, tt.userID, tt.data)
+			got, err := c.CreateChannel(ctx, tt.channelType, tt.id, tt.userID, tt.data)
 			if tt.wantErr {
 				require.Error(t, err, "create channel", tt)
 				return
@@ -68,13 +71,15 @@ func TestClient_CreateChannel(t *testing.T) {
 }
 
 func TestChannel_AddMembers(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 
 	chanID := randomString(12)
-	ch, err := c.CreateChannel("messaging", chanID, randomUser(t, c).ID, nil)
+	ch, err := c.CreateChannel(ctx, "messaging", chanID, randomUser(t, c).ID, nil)
 	require.NoError(t, err, "create channel")
 	defer func() {
-		_ = ch.Delete()
+		_ = ch.Delete(ctx)
 	}()
 
 	assert.Empty(t, ch.Members, "members are empty")
@@ -83,7 +88,7 @@ func TestChannel_AddMembers(t *testing.T) {
 	options := map[string]interface{}{
 		"hide_history": true,
 	}
-	err = ch.AddMembers(
+	err = ch.AddMembers(ctx,
 		[]string{user.ID},
 		&Message{Text: "some members", User: &User{ID: user.ID}},
 		options,
@@ -91,20 +96,22 @@ func TestChannel_AddMembers(t *testing.T) {
 	require.NoError(t, err, "add members")
 
 	// refresh channel state
-	require.NoError(t, ch.refresh(), "refresh channel")
+	require.NoError(t, ch.refresh(ctx), "refresh channel")
 	assert.Equal(t, user.ID, ch.Members[0].User.ID, "members contain user id")
 }
 
 func TestChannel_ImportChannelMessages(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 
 	owner := randomUser(t, c)
 	chanID := randomString(12)
 
-	ch, err := c.CreateChannel("messaging", chanID, owner.ID, nil)
+	ch, err := c.CreateChannel(ctx, "messaging", chanID, owner.ID, nil)
 	require.NoError(t, err, "create channel")
 	defer func() {
-		_ = ch.Delete()
+		_ = ch.Delete(ctx)
 	}()
 
 	assert.Empty(t, ch.Members, "members are empty")
@@ -113,7 +120,7 @@ func TestChannel_ImportChannelMessages(t *testing.T) {
 
 	t0 := time.Unix(0, 0).UTC()
 	t1 := time.Unix(1, 0).UTC()
-	resp, err := ch.ImportMessages(
+	resp, err := ch.ImportMessages(ctx,
 		&Message{
 			Text:      "hi 1",
 			User:      &User{ID: user.ID},
@@ -129,20 +136,22 @@ func TestChannel_ImportChannelMessages(t *testing.T) {
 	require.Equal(t, &t1, resp.Messages[1].CreatedAt)
 
 	// get the channel and validate last_message_at
-	ch, err = c.CreateChannel("messaging", chanID, owner.ID, nil)
+	ch, err = c.CreateChannel(ctx, "messaging", chanID, owner.ID, nil)
 	require.NoError(t, err, "create channel")
 	require.Equal(t, t1, ch.LastMessageAt)
 }
 
 func TestChannel_QueryMembers(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 
 	chanID := randomString(12)
 
-	ch, err := c.CreateChannel("messaging", chanID, randomUser(t, c).ID, nil)
+	ch, err := c.CreateChannel(ctx, "messaging", chanID, randomUser(t, c).ID, nil)
 	require.NoError(t, err, "create channel")
 	defer func() {
-		_ = ch.Delete()
+		_ = ch.Delete(ctx)
 	}()
 
 	assert.Empty(t, ch.Members, "members are empty")
@@ -152,12 +161,12 @@ func TestChannel_QueryMembers(t *testing.T) {
 
 	for _, name := range names {
 		id := prefix + name
-		_, err := c.UpsertUser(&User{ID: id, Name: id})
+		_, err := c.UpsertUser(ctx, &User{ID: id, Name: id})
 		require.NoError(t, err)
-		require.NoError(t, ch.AddMembers([]string{id}, nil, nil))
+		require.NoError(t, ch.AddMembers(ctx, []string{id}, nil, nil))
 	}
 
-	members, err := ch.QueryMembers(&QueryOption{
+	members, err := ch.QueryMembers(ctx, &QueryOption{
 		Filter: map[string]interface{}{
 			"name": map[string]interface{}{"$autocomplete": prefix + "j"},
 		},
@@ -173,34 +182,38 @@ func TestChannel_QueryMembers(t *testing.T) {
 
 // See https://getstream.io/chat/docs/channel_members/ for more details.
 func ExampleChannel_AddModerators() {
+	ctx := context.Background()
+
 	channel := &Channel{}
 	newModerators := []string{"bob", "sue"}
 
-	_ = channel.AddModerators("thierry", "josh")
-	_ = channel.AddModerators(newModerators...)
-	_ = channel.DemoteModerators(newModerators...)
+	_ = channel.AddModerators(ctx, "thierry", "josh")
+	_ = channel.AddModerators(ctx, newModerators...)
+	_ = channel.DemoteModerators(ctx, newModerators...)
 }
 
 func TestChannel_InviteMembers(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 
 	chanID := randomString(12)
 
-	ch, err := c.CreateChannel("messaging", chanID, randomUser(t, c).ID, nil)
+	ch, err := c.CreateChannel(ctx, "messaging", chanID, randomUser(t, c).ID, nil)
 	require.NoError(t, err, "create channel")
 	defer func() {
-		_ = ch.Delete()
+		_ = ch.Delete(ctx)
 	}()
 
 	assert.Empty(t, ch.Members, "members are empty")
 
 	user := randomUser(t, c)
 
-	err = ch.InviteMembers(user.ID)
+	err = ch.InviteMembers(ctx, user.ID)
 	require.NoError(t, err, "invite members")
 
 	// refresh channel state
-	require.NoError(t, ch.refresh(), "refresh channel")
+	require.NoError(t, ch.refresh(ctx), "refresh channel")
 
 	assert.Equal(t, user.ID, ch.Members[0].User.ID, "members contain user id")
 	assert.Equal(t, true, ch.Members[0].Invited, "member is invited")
@@ -209,21 +222,23 @@ func TestChannel_InviteMembers(t *testing.T) {
 }
 
 func TestChannel_Moderation(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 
 	// init random channel
 	chanID := randomString(12)
-	ch, err := c.CreateChannel("messaging", chanID, randomUser(t, c).ID, nil)
+	ch, err := c.CreateChannel(ctx, "messaging", chanID, randomUser(t, c).ID, nil)
 	require.NoError(t, err, "create channel")
 	defer func() {
-		_ = ch.Delete()
+		_ = ch.Delete(ctx)
 	}()
 
 	assert.Empty(t, ch.Members, "members are empty")
 
 	user := randomUser(t, c)
 
-	err = ch.AddModeratorsWithMessage(
+	err = ch.AddModeratorsWithMessage(ctx,
 		[]string{user.ID},
 		&Message{Text: "accepted", User: &User{ID: user.ID}},
 	)
@@ -231,68 +246,74 @@ func TestChannel_Moderation(t *testing.T) {
 	require.NoError(t, err, "add moderators")
 
 	// refresh channel state
-	require.NoError(t, ch.refresh(), "refresh channel")
+	require.NoError(t, ch.refresh(ctx), "refresh channel")
 
 	assert.Equal(t, user.ID, ch.Members[0].User.ID, "user exists")
 	assert.Equal(t, "moderator", ch.Members[0].Role, "user role is moderator")
 
-	err = ch.DemoteModerators(user.ID)
+	err = ch.DemoteModerators(ctx, user.ID)
 	require.NoError(t, err, "demote moderators")
 
 	// refresh channel state
-	require.NoError(t, ch.refresh(), "refresh channel")
+	require.NoError(t, ch.refresh(ctx), "refresh channel")
 
 	assert.Equal(t, user.ID, ch.Members[0].User.ID, "user exists")
 	assert.Equal(t, "member", ch.Members[0].Role, "user role is member")
 }
 
 func TestChannel_BanUser(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 	ch := initChannel(t, c)
 	defer func() {
-		_ = ch.Delete()
+		_ = ch.Delete(ctx)
 	}()
 
 	user := randomUser(t, c)
 	target := randomUser(t, c)
 
-	err := ch.BanUser(target.ID, user.ID, nil)
+	err := ch.BanUser(ctx, target.ID, user.ID, nil)
 	require.NoError(t, err, "ban user")
 
-	err = ch.BanUser(target.ID, user.ID, map[string]interface{}{
+	err = ch.BanUser(ctx, target.ID, user.ID, map[string]interface{}{
 		"timeout": 3600,
 		"reason":  "offensive language is not allowed here",
 	})
 	require.NoError(t, err, "ban user")
 
-	err = ch.UnBanUser(target.ID, nil)
+	err = ch.UnBanUser(ctx, target.ID, nil)
 	require.NoError(t, err, "unban user")
 }
 
 func TestChannel_Delete(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 	ch := initChannel(t, c)
 
-	require.NoError(t, ch.Delete(), "delete channel")
+	require.NoError(t, ch.Delete(ctx), "delete channel")
 }
 
 func TestChannel_GetReplies(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 	ch := initChannel(t, c)
 	defer func() {
-		_ = ch.Delete()
+		_ = ch.Delete(ctx)
 	}()
 
 	msg := &Message{Text: "test message"}
 
-	msg, err := ch.SendMessage(msg, randomUser(t, c).ID, MessageSkipPush)
+	msg, err := ch.SendMessage(ctx, msg, randomUser(t, c).ID, MessageSkipPush)
 	require.NoError(t, err, "send message")
 
 	reply := &Message{Text: "test reply", ParentID: msg.ID, Type: MessageTypeReply}
-	_, err = ch.SendMessage(reply, randomUser(t, c).ID)
+	_, err = ch.SendMessage(ctx, reply, randomUser(t, c).ID)
 	require.NoError(t, err, "send reply")
 
-	replies, err := ch.GetReplies(msg.ID, nil)
+	replies, err := ch.GetReplies(ctx, msg.ID, nil)
 	require.NoError(t, err, "get replies")
 	assert.Len(t, replies, 1)
 }
@@ -301,14 +322,16 @@ func TestChannel_MarkRead(t *testing.T) {
 }
 
 func TestChannel_RemoveMembers(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 	ch := initChannel(t, c)
 	defer func() {
-		_ = ch.Delete()
+		_ = ch.Delete(ctx)
 	}()
 
 	user := randomUser(t, c)
-	err := ch.RemoveMembers(
+	err := ch.RemoveMembers(ctx,
 		[]string{user.ID},
 		&Message{Text: "some members", User: &User{ID: user.ID}},
 	)
@@ -324,10 +347,12 @@ func TestChannel_SendEvent(t *testing.T) {
 }
 
 func TestChannel_SendMessage(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 	ch := initChannel(t, c)
 	defer func() {
-		_ = ch.Delete()
+		_ = ch.Delete(ctx)
 	}()
 
 	user1 := randomUser(t, c)
@@ -337,7 +362,7 @@ func TestChannel_SendMessage(t *testing.T) {
 		User: user1,
 	}
 
-	msg, err := ch.SendMessage(msg, user2.ID)
+	msg, err := ch.SendMessage(ctx, msg, user2.ID)
 	require.NoError(t, err, "send message")
 	// check that message was updated
 	assert.NotEmpty(t, msg.ID, "message has ID")
@@ -348,7 +373,7 @@ func TestChannel_SendMessage(t *testing.T) {
 		User:   user1,
 		Silent: true,
 	}
-	msg2, err = ch.SendMessage(msg2, user2.ID)
+	msg2, err = ch.SendMessage(ctx, msg2, user2.ID)
 	require.NoError(t, err, "send message 2")
 	// check that message was updated
 	assert.NotEmpty(t, msg2.ID, "message has ID")
@@ -356,11 +381,79 @@ func TestChannel_SendMessage(t *testing.T) {
 	assert.True(t, msg2.Silent, "message silent flag is set")
 }
 
+func TestChannel_SendMessageIdempotent(t *testing.T) {
+	ctx := context.Background()
+
+	c := initClient(t)
+	ch := initChannel(t, c)
+	defer func() {
+		_ = ch.Delete(ctx)
+	}()
+	user := randomUser(t, c)
+
+	t.Run("caller supplied ID", func(t *testing.T) {
+		id := randomString(12)
+		msg, err := ch.SendMessage(ctx, &Message{ID: id, Text: "first"}, user.ID)
+		require.NoError(t, err)
+		require.Equal(t, id, msg.ID)
+
+		again, err := ch.SendMessage(ctx, &Message{ID: id, Text: "second"}, user.ID)
+		require.NoError(t, err, "an existing message is not an error")
+		require.Equal(t, id, again.ID)
+		require.Equal(t, "first", again.Text)
+	})
+
+	t.Run("ID of a message of another channel or user", func(t *testing.T) {
+		id := randomString(12)
+		_, err := ch.SendMessage(ctx, &Message{ID: id, Text: "first"}, user.ID)
+		require.NoError(t, err)
+
+		other := initChannel(t, c)
+		defer func() {
+			_ = other.Delete(ctx)
+		}()
+		_, err = other.SendMessage(ctx, &Message{ID: id, Text: "second"}, user.ID)
+		require.True(t, IsInputError(err), "the message of another channel is not returned")
+
+		_, err = ch.SendMessage(ctx, &Message{ID: id, Text: "second"}, randomUser(t, c).ID)
+		require.True(t, IsInputError(err), "the message of another user is not returned")
+	})
+
+	t.Run("ID from key", func(t *testing.T) {
+		key := randomString(12)
+		msg, err := ch.SendMessage(ctx, &Message{Text: "keyed"}, user.ID, MessageIDFromKey(key))
+		require.NoError(t, err)
+		require.Equal(t, keyUUID(key), msg.ID)
+
+		again, err := ch.SendMessage(ctx, &Message{Text: "keyed"}, user.ID, MessageIDFromKey(key))
+		require.NoError(t, err)
+		require.Equal(t, msg.ID, again.ID)
+	})
+
+	t.Run("auto ID", func(t *testing.T) {
+		msg := &Message{Text: "auto"}
+		got, err := ch.SendMessage(ctx, msg, user.ID, MessageAutoID)
+		require.NoError(t, err)
+		require.NotEmpty(t, got.ID)
+		require.NotEqual(t, keyUUID(""), got.ID)
+	})
+}
+
+func TestKeyUUID(t *testing.T) {
+	id := keyUUID("order-42")
+	require.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, id)
+	require.Equal(t, id, keyUUID("order-42"))
+	require.NotEqual(t, id, keyUUID("order-43"))
+	require.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, randomUUID())
+}
+
 func TestChannel_Truncate(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 	ch := initChannel(t, c)
 	defer func() {
-		_ = ch.Delete()
+		_ = ch.Delete(ctx)
 	}()
 
 	user := randomUser(t, c)
@@ -368,33 +461,37 @@ func TestChannel_Truncate(t *testing.T) {
 		Text: "test message",
 		User: user,
 	}
-	msg, err := ch.SendMessage(msg, user.ID)
+	msg, err := ch.SendMessage(ctx, msg, user.ID)
 	require.NoError(t, err, "send message")
 
 	// refresh channel state
-	require.NoError(t, ch.refresh(), "refresh channel")
+	require.NoError(t, ch.refresh(ctx), "refresh channel")
 
 	assert.Equal(t, ch.Messages[0].ID, msg.ID, "message exists")
 
-	err = ch.Truncate()
+	err = ch.Truncate(ctx)
 	require.NoError(t, err, "truncate channel")
 
 	// refresh channel state
-	require.NoError(t, ch.refresh(), "refresh channel")
+	require.NoError(t, ch.refresh(ctx), "refresh channel")
 
 	assert.Empty(t, ch.Messages, "message not exists")
 }
 
 func TestChannel_Update(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 	ch := initChannel(t, c)
 
-	err := ch.Update(map[string]interface{}{"color": "blue"},
+	err := ch.Update(ctx, map[string]interface{}{"color": "blue"},
 		&Message{Text: "color is blue", User: &User{ID: randomUser(t, c).ID}})
 	require.NoError(t, err)
 }
 
 func TestChannel_PartialUpdate(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 	users := randomUsers(t, c, 5)
 
@@ -404,20 +501,20 @@ func TestChannel_PartialUpdate(t *testing.T) {
 	}
 
 	var ch *Channel
-	ch, err := c.CreateChannel("team", randomString(12), randomUser(t, c).ID, map[string]interface{}{
+	ch, err := c.CreateChannel(ctx, "team", randomString(12), randomUser(t, c).ID, map[string]interface{}{
 		"members": members,
 		"color":   "blue",
 		"age":     30,
 	})
 	require.NoError(t, err)
-	err = ch.PartialUpdate(PartialUpdate{
+	err = ch.PartialUpdate(ctx, PartialUpdate{
 		Set: map[string]interface{}{
 			"color": "red",
 		},
 		Unset: []string{"age"},
 	})
 	require.NoError(t, err)
-	err = ch.refresh()
+	err = ch.refresh(ctx)
 	require.NoError(t, err)
 	require.Equal(t, "red", ch.ExtraData["color"])
 	require.Equal(t, nil, ch.ExtraData["age"])
@@ -433,6 +530,8 @@ func TestChannel_UnBanUser(t *testing.T) {
 }
 
 func TestChannel_SendFile(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 	ch := initChannel(t, c)
 
@@ -444,7 +543,7 @@ func TestChannel_SendFile(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		url, err = ch.SendFile(SendFileRequest{
+		url, err = ch.SendFile(ctx, SendFileRequest{
 			Reader:   file,
 			FileName: "HelloWorld.txt",
 			User:     randomUser(t, c),
@@ -458,7 +557,7 @@ func TestChannel_SendFile(t *testing.T) {
 	})
 
 	t.Run("Delete file", func(t *testing.T) {
-		err := ch.DeleteFile(url)
+		err := ch.DeleteFile(ctx, url)
 		if err != nil {
 			t.Fatalf("delete file failed: %s", err.Error())
 		}
@@ -466,6 +565,8 @@ func TestChannel_SendFile(t *testing.T) {
 }
 
 func TestChannel_SendImage(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 	ch := initChannel(t, c)
 
@@ -477,7 +578,7 @@ func TestChannel_SendImage(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		url, err = ch.SendImage(SendFileRequest{
+		url, err = ch.SendImage(ctx, SendFileRequest{
 			Reader:      file,
 			FileName:    "HelloWorld.jpg",
 			User:        randomUser(t, c),
@@ -494,7 +595,7 @@ func TestChannel_SendImage(t *testing.T) {
 	})
 
 	t.Run("Delete image", func(t *testing.T) {
-		err := ch.DeleteImage(url)
+		err := ch.DeleteImage(ctx, url)
 		if err != nil {
 			t.Fatalf("delete image failed: %s", err.Error())
 		}
@@ -502,6 +603,8 @@ func TestChannel_SendImage(t *testing.T) {
 }
 
 func TestChannel_AcceptInvite(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 
 	users := randomUsers(t, c, 5)
@@ -511,17 +614,19 @@ func TestChannel_AcceptInvite(t *testing.T) {
 		members = append(members, users[i].ID)
 	}
 
-	ch, err := c.CreateChannel("team", randomString(12), randomUser(t, c).ID, map[string]interface{}{
+	ch, err := c.CreateChannel(ctx, "team", randomString(12), randomUser(t, c).ID, map[string]interface{}{
 		"members": members,
 		"invites": []string{members[0]},
 	})
 
 	require.NoError(t, err, "create channel")
-	err = ch.AcceptInvite(members[0], &Message{Text: "accepted", User: &User{ID: members[0]}})
+	err = ch.AcceptInvite(ctx, members[0], &Message{Text: "accepted", User: &User{ID: members[0]}})
 	require.NoError(t, err, "accept invite")
 }
 
 func TestChannel_RejectInvite(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 
 	users := randomUsers(t, c, 5)
@@ -531,17 +636,19 @@ func TestChannel_RejectInvite(t *testing.T) {
 		members = append(members, users[i].ID)
 	}
 
-	ch, err := c.CreateChannel("team", randomString(12), randomUser(t, c).ID, map[string]interface{}{
+	ch, err := c.CreateChannel(ctx, "team", randomString(12), randomUser(t, c).ID, map[string]interface{}{
 		"members": members,
 		"invites": []string{members[0]},
 	})
 
 	require.NoError(t, err, "create channel")
-	err = ch.RejectInvite(members[0], &Message{Text: "rejected", User: &User{ID: members[0]}})
+	err = ch.RejectInvite(ctx, members[0], &Message{Text: "rejected", User: &User{ID: members[0]}})
 	require.NoError(t, err, "reject invite")
 }
 
 func TestChannel_Mute_Unmute(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 
 	users := randomUsers(t, c, 5)
@@ -551,19 +658,19 @@ func TestChannel_Mute_Unmute(t *testing.T) {
 		members = append(members, users[i].ID)
 	}
 
-	ch, err := c.CreateChannel("messaging", randomString(12), randomUser(t, c).ID, map[string]interface{}{
+	ch, err := c.CreateChannel(ctx, "messaging", randomString(12), randomUser(t, c).ID, map[string]interface{}{
 		"members": members,
 	})
 	require.NoError(t, err, "create channel")
 
 	// mute the channel
-	mute, err := ch.Mute(members[0], nil)
+	mute, err := ch.Mute(ctx, members[0], nil)
 	require.NoError(t, err, "mute channel")
 
 	require.Equal(t, ch.CID, mute.ChannelMute.Channel.CID)
 	require.Equal(t, members[0], mute.ChannelMute.User.ID)
 	// query for muted the channel
-	channels, err := c.QueryChannels(&QueryOption{
+	channels, err := c.QueryChannels(ctx, &QueryOption{
 		UserID: members[0],
 		Filter: map[string]interface{}{
 			"muted": true,
@@ -575,11 +682,11 @@ func TestChannel_Mute_Unmute(t *testing.T) {
 	require.Equal(t, channels[0].CID, ch.CID)
 
 	// unmute the channel
-	err = ch.Unmute(members[0])
+	err = ch.Unmute(ctx, members[0])
 	require.NoError(t, err, "mute channel")
 
 	// query for unmuted the channel should return 1 results
-	channels, err = c.QueryChannels(&QueryOption{
+	channels, err = c.QueryChannels(ctx, &QueryOption{
 		UserID: members[0],
 		Filter: map[string]interface{}{
 			"muted": false,
@@ -591,6 +698,8 @@ func TestChannel_Mute_Unmute(t *testing.T) {
 }
 
 func ExampleChannel_Update() {
+	ctx := context.Background()
+
 	// https://getstream.io/chat/docs/channel_permissions/?language=python
 	client := &Client{}
 
@@ -601,16 +710,18 @@ func ExampleChannel_Update() {
 	}
 
 	spacexChannel := client.Channel("team", "spacex")
-	if err := spacexChannel.Update(data, nil); err != nil {
+	if err := spacexChannel.Update(ctx, data, nil); err != nil {
 		log.Fatalf("Error: %v", err)
 	}
 }
 
 func (c *Client) ExampleClient_CreateChannel() {
+	ctx := context.Background()
+
 	client, _ := NewClient("XXXX", "XXXX")
 
-	channel, _ := client.CreateChannel("team", "stream", "tommaso", nil)
-	_, _ = channel.SendMessage(&Message{
+	channel, _ := client.CreateChannel(ctx, "team", "stream", "tommaso", nil)
+	_, _ = channel.SendMessage(ctx, &Message{
 		User: &User{ID: "tomosso"},
 		Text: "hi there!",
 	}, "tomosso")
@@ -1,6 +1,7 @@
 package stream_chat // nolint: golint
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -9,9 +10,11 @@ import (
 )
 
 func prepareChannelType(t *testing.T, c *Client) *ChannelType {
+	ctx := context.Background()
+
 	ct := NewChannelType(randomString(10))
 
-	ct, err := c.CreateChannelType(ct)
+	ct, err := c.CreateChannelType(ctx, ct)
 	require.NoError(t, err, "create channel type")
 
 	time.Sleep(6 * time.Second)
@@ -20,14 +23,16 @@ func prepareChannelType(t *testing.T, c *Client) *ChannelType {
 }
 
 func TestClient_GetChannelType(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 
 	ct := prepareChannelType(t, c)
 	defer func() {
-		_ = c.DeleteChannelType(ct.Name)
+		_ = c.DeleteChannelType(ctx, ct.Name)
 	}()
 
-	got, err := c.GetChannelType(ct.Name)
+	got, err := c.GetChannelType(ctx, ct.Name)
 	require.NoError(t, err, "get channel type")
 
 	assert.Equal(t, ct.Name, got.Name)
@@ -36,40 +41,46 @@ func TestClient_GetChannelType(t *testing.T) {
 }
 
 func TestClient_ListChannelTypes(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 
 	ct := prepareChannelType(t, c)
 	defer func() {
-		_ = c.DeleteChannelType(ct.Name)
+		_ = c.DeleteChannelType(ctx, ct.Name)
 	}()
 
-	got, err := c.ListChannelTypes()
+	got, err := c.ListChannelTypes(ctx)
 	require.NoError(t, err, "list channel types")
 
 	assert.Contains(t, got, ct.Name)
 }
 
 func TestClient_UpdateChannelTypePushNotifications(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 
 	ct := prepareChannelType(t, c)
 	defer func() {
-		_ = c.DeleteChannelType(ct.Name)
+		_ = c.DeleteChannelType(ctx, ct.Name)
 	}()
 
 	// default is on
 	require.True(t, ct.PushNotifications)
 
-	err := c.UpdateChannelType(ct.Name, map[string]interface{}{"push_notifications": false})
+	err := c.UpdateChannelType(ctx, ct.Name, map[string]interface{}{"push_notifications": false})
 	require.NoError(t, err)
 
-	updated, err := c.GetChannelType(ct.Name)
+	updated, err := c.GetChannelType(ctx, ct.Name)
 	require.NoError(t, err)
 	require.False(t, updated.PushNotifications)
 }
 
 // See https://getstream.io/chat/docs/channel_features/ for more details.
 func ExampleClient_CreateChannelType() {
+	ctx := context.Background()
+
 	client := &Client{}
 
 	newChannelType := &ChannelType{
@@ -95,23 +106,29 @@ func ExampleClient_CreateChannelType() {
 		},
 	)
 
-	_, _ = client.CreateChannelType(newChannelType)
+	_, _ = client.CreateChannelType(ctx, newChannelType)
 }
 
 func ExampleClient_ListChannelTypes() {
+	ctx := context.Background()
+
 	client := &Client{}
-	_, _ = client.ListChannelTypes()
+	_, _ = client.ListChannelTypes(ctx)
 }
 
 func ExampleClient_GetChannelType() {
+	ctx := context.Background()
+
 	client := &Client{}
-	_, _ = client.GetChannelType("public")
+	_, _ = client.GetChannelType(ctx, "public")
 }
 
 func ExampleClient_UpdateChannelType() {
+	ctx := context.Background()
+
 	client := &Client{}
 
-	_ = client.UpdateChannelType("public", map[string]interface{}{
+	_ = client.UpdateChannelType(ctx, "public", map[string]interface{}{
 		"permissions": []map[string]interface{}{
 			{
 				"name":      "Allow reads for all",
@@ -134,9 +151,11 @@ func ExampleClient_UpdateChannelType() {
 }
 
 func ExampleClient_UpdateChannelType_bool() {
+	ctx := context.Background()
+
 	client := &Client{}
 
-	_ = client.UpdateChannelType("public", map[string]interface{}{
+	_ = client.UpdateChannelType(ctx, "public", map[string]interface{}{
 		"typing_events":  false,
 		"read_events":    true,
 		"connect_events": true,
@@ -148,9 +167,11 @@ func ExampleClient_UpdateChannelType_bool() {
 }
 
 func ExampleClient_UpdateChannelType_other() {
+	ctx := context.Background()
+
 	client := &Client{}
 
-	_ = client.UpdateChannelType(
+	_ = client.UpdateChannelType(ctx,
 		"public",
 		map[string]interface{}{
 			"automod":            "disabled",
@@ -162,9 +183,11 @@ func ExampleClient_UpdateChannelType_other() {
 }
 
 func ExampleClient_UpdateChannelType_permissions() {
+	ctx := context.Background()
+
 	client := &Client{}
 
-	_ = client.UpdateChannelType(
+	_ = client.UpdateChannelType(ctx,
 		"public",
 		map[string]interface{}{
 			"permissions": []map[string]interface{}{
@@ -187,7 +210,9 @@ func ExampleClient_UpdateChannelType_permissions() {
 }
 
 func ExampleClient_DeleteChannelType() {
+	ctx := context.Background()
+
 	client := &Client{}
 
-	_ = client.DeleteChannelType("public")
+	_ = client.DeleteChannelType(ctx, "public")
 }
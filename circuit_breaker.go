@@ -0,0 +1,331 @@
+package stream_chat // nolint: golint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Endpoint groups of the circuit breaker, returned by DefaultEndpointGroup.
+const (
+	EndpointGroupMessages   = "messages"
+	EndpointGroupUsers      = "users"
+	EndpointGroupModeration = "moderation"
+	EndpointGroupChannels   = "channels"
+	EndpointGroupOther      = "other"
+)
+
+// ErrCircuitOpen matches the CircuitOpenError returned while a circuit breaker is open,
+// with errors.Is.
+var ErrCircuitOpen = errors.New("chat-client: circuit breaker open")
+
+// CircuitOpenError is returned without sending the request while the circuit breaker of the
+// endpoint group is open.
+type CircuitOpenError struct {
+	// Group is the endpoint group of the request.
+	Group string
+	// RetryAfter is the time left before the circuit breaker lets a probe request through.
+	RetryAfter time.Duration
+}
+
+// Error implements error.
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%v for %s endpoints, retry in %s", ErrCircuitOpen, e.Group, e.RetryAfter)
+}
+
+// Is makes errors.Is(err, ErrCircuitOpen) true.
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// IsCircuitOpen reports whether err was returned because a circuit breaker is open.
+func IsCircuitOpen(err error) bool {
+	return errors.Is(err, ErrCircuitOpen)
+}
+
+// CircuitState is the state of the circuit breaker of an endpoint group.
+type CircuitState int
+
+const (
+	// CircuitClosed lets all the requests through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects all the requests with a CircuitOpenError.
+	CircuitOpen
+	// CircuitHalfOpen lets a few probe requests through to find out whether the API recovered.
+	CircuitHalfOpen
+)
+
+// String implements fmt.Stringer.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitBreakerConfig configures the circuit breaker enabled with WithCircuitBreaker.
+//
+// Every endpoint group has its own circuit. A circuit opens when the rate of failed requests
+// over Window reaches FailureRate, with at least MinRequests requests. Requests fail with a
+// CircuitOpenError for Cooldown, then up to HalfOpenProbes requests are let through: the circuit
+// closes once they all succeed and opens again as soon as one fails.
+//
+// A request fails when no response is received or the status is a server error. Rate limited
+// requests and requests canceled by the caller are not counted.
+type CircuitBreakerConfig struct {
+	// FailureRate is the ratio of failed requests, between 0 and 1, opening the circuit.
+	FailureRate float64
+	// MinRequests is the minimum number of requests in the window to open the circuit.
+	MinRequests int
+	// Window is the period over which the failure rate is measured.
+	Window time.Duration
+	// Cooldown is how long the circuit stays open before probing the API.
+	Cooldown time.Duration
+	// HalfOpenProbes is the number of successful probe requests closing the circuit.
+	HalfOpenProbes int
+	// EndpointGroup returns the group of an API endpoint, like "SendMessage".
+	// DefaultEndpointGroup is used if nil.
+	EndpointGroup func(endpoint string) string
+	// OnStateChange is called when the circuit of a group changes state, if not nil.
+	OnStateChange func(group string, from, to CircuitState)
+}
+
+// DefaultCircuitBreakerConfig returns a CircuitBreakerConfig opening the circuit when half of at
+// least 10 requests failed in 30 seconds, for 15 seconds, with one probe request.
+func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
+	return CircuitBreakerConfig{
+		FailureRate:    0.5,
+		MinRequests:    10,
+		Window:         30 * time.Second,
+		Cooldown:       15 * time.Second,
+		HalfOpenProbes: 1,
+	}
+}
+
+// WithCircuitBreaker enables a circuit breaker failing the requests fast while the API is failing.
+func WithCircuitBreaker(config CircuitBreakerConfig) ClientOption {
+	return func(o *clientOptions) {
+		o.circuitBreaker = &config
+	}
+}
+
+// DefaultEndpointGroup returns the group of an API endpoint: EndpointGroupMessages,
+// EndpointGroupUsers, EndpointGroupModeration, EndpointGroupChannels or EndpointGroupOther.
+func DefaultEndpointGroup(endpoint string) string {
+	switch endpoint {
+	case "SendMessage", "GetMessage", "UpdateMessage", "UpdateMessagePartial", "DeleteMessage",
+		"GetReplies", "RunMessageAction", "SendReaction", "DeleteReaction", "GetReactions", "Search",
+		"ImportChannelMessages", "UploadFile", "DeleteFile", "UploadImage", "DeleteImage":
+		return EndpointGroupMessages
+	case "QueryUsers", "UpdateUsers", "UpdateUsersPartial", "DeleteUsers", "DeleteUser", "ExportUser",
+		"DeactivateUser", "ReactivateUser", "SendUserCustomEvent", "ListDevices", "CreateDevice", "DeleteDevice":
+		return EndpointGroupUsers
+	case "MuteUser", "UnmuteUser", "MuteChannel", "UnmuteChannel", "Flag", "Unflag", "Ban", "Unban",
+		"QueryMessageFlags":
+		return EndpointGroupModeration
+	case "QueryChannels", "MarkChannelsRead", "DeleteChannels", "GetOrCreateChannel", "UpdateChannel",
+		"UpdateChannelPartial", "DeleteChannel", "TruncateChannel", "MarkRead", "ShowChannel", "HideChannel",
+		"SendEvent", "QueryMembers", "ExportChannels", "GetExportChannelsStatus":
+		return EndpointGroupChannels
+	default:
+		return EndpointGroupOther
+	}
+}
+
+type circuitOutcome int
+
+const (
+	circuitSuccess circuitOutcome = iota
+	circuitFailure
+	circuitIgnored
+)
+
+// requestOutcome classifies the result of a request for the circuit breaker.
+func requestOutcome(ctx context.Context, resp *http.Response, err error) circuitOutcome {
+	switch {
+	case err != nil && ctx.Err() != nil:
+		return circuitIgnored
+	case err != nil:
+		return circuitFailure
+	case resp.StatusCode >= 500:
+		return circuitFailure
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return circuitIgnored
+	default:
+		return circuitSuccess
+	}
+}
+
+// circuit is the state of the circuit breaker of an endpoint group.
+type circuit struct {
+	state    CircuitState
+	openedAt time.Time
+
+	// requests and failures are counted since windowStart in the closed state
+	windowStart time.Time
+	requests    int
+	failures    int
+
+	// probes in flight and successful in the half-open state
+	probes    int
+	successes int
+}
+
+type circuitBreaker struct {
+	config CircuitBreakerConfig
+	logf   func(format string, v ...interface{})
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+func newCircuitBreaker(config CircuitBreakerConfig, logf func(format string, v ...interface{})) *circuitBreaker {
+	defaults := DefaultCircuitBreakerConfig()
+	if config.FailureRate <= 0 {
+		config.FailureRate = defaults.FailureRate
+	}
+	if config.MinRequests <= 0 {
+		config.MinRequests = defaults.MinRequests
+	}
+	if config.Window <= 0 {
+		config.Window = defaults.Window
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = defaults.Cooldown
+	}
+	if config.HalfOpenProbes <= 0 {
+		config.HalfOpenProbes = defaults.HalfOpenProbes
+	}
+	if config.EndpointGroup == nil {
+		config.EndpointGroup = DefaultEndpointGroup
+	}
+	return &circuitBreaker{config: config, logf: logf, circuits: make(map[string]*circuit)}
+}
+
+// allow checks whether a request to endpoint may be sent. If so, the returned function must be
+// called with the outcome of the request.
+func (b *circuitBreaker) allow(endpoint string) (func(circuitOutcome), error) {
+	group := b.config.EndpointGroup(endpoint)
+
+	var transition *circuitTransition
+	b.mu.Lock()
+	defer func() {
+		b.mu.Unlock()
+		b.notify(transition)
+	}()
+
+	c, ok := b.circuits[group]
+	if !ok {
+		c = &circuit{windowStart: time.Now()}
+		b.circuits[group] = c
+	}
+
+	now := time.Now()
+	if c.state == CircuitOpen {
+		if wait := c.openedAt.Add(b.config.Cooldown).Sub(now); wait > 0 {
+			return nil, &CircuitOpenError{Group: group, RetryAfter: wait}
+		}
+		transition = b.setState(group, c, CircuitHalfOpen)
+	}
+
+	if c.state == CircuitHalfOpen {
+		if c.probes+c.successes >= b.config.HalfOpenProbes {
+			return nil, &CircuitOpenError{Group: group}
+		}
+		c.probes++
+		return func(outcome circuitOutcome) { b.recordProbe(group, c, outcome) }, nil
+	}
+
+	return func(outcome circuitOutcome) { b.record(group, c, outcome) }, nil
+}
+
+// record counts the outcome of a request sent while the circuit was closed.
+func (b *circuitBreaker) record(group string, c *circuit, outcome circuitOutcome) {
+	var transition *circuitTransition
+	b.mu.Lock()
+	defer func() {
+		b.mu.Unlock()
+		b.notify(transition)
+	}()
+
+	if outcome == circuitIgnored || c.state != CircuitClosed {
+		return
+	}
+
+	now := time.Now()
+	if now.Sub(c.windowStart) >= b.config.Window {
+		c.windowStart, c.requests, c.failures = now, 0, 0
+	}
+	c.requests++
+	if outcome == circuitFailure {
+		c.failures++
+	}
+
+	if c.requests >= b.config.MinRequests && float64(c.failures)/float64(c.requests) >= b.config.FailureRate {
+		c.openedAt = now
+		transition = b.setState(group, c, CircuitOpen)
+	}
+}
+
+// recordProbe handles the outcome of a probe request sent while the circuit was half-open.
+func (b *circuitBreaker) recordProbe(group string, c *circuit, outcome circuitOutcome) {
+	var transition *circuitTransition
+	b.mu.Lock()
+	defer func() {
+		b.mu.Unlock()
+		b.notify(transition)
+	}()
+
+	c.probes--
+	if c.state != CircuitHalfOpen {
+		return
+	}
+
+	switch outcome {
+	case circuitFailure:
+		c.openedAt = time.Now()
+		transition = b.setState(group, c, CircuitOpen)
+	case circuitSuccess:
+		c.successes++
+		if c.successes >= b.config.HalfOpenProbes {
+			c.windowStart, c.requests, c.failures = time.Now(), 0, 0
+			transition = b.setState(group, c, CircuitClosed)
+		}
+	}
+}
+
+// circuitTransition is a state change of the circuit of a group.
+type circuitTransition struct {
+	group    string
+	from, to CircuitState
+}
+
+// setState changes the state of the circuit, with b.mu held. The returned transition must be
+// given to notify once b.mu is released.
+func (b *circuitBreaker) setState(group string, c *circuit, state CircuitState) *circuitTransition {
+	from := c.state
+	c.state = state
+	c.successes = 0
+	return &circuitTransition{group: group, from: from, to: state}
+}
+
+// notify logs the transition and calls OnStateChange, without b.mu held so that they can
+// use the client. It does nothing if t is nil.
+func (b *circuitBreaker) notify(t *circuitTransition) {
+	if t == nil {
+		return
+	}
+	b.logf("chat-client: circuit breaker for %s endpoints is %s", t.group, t.to)
+	if b.config.OnStateChange != nil {
+		b.config.OnStateChange(t.group, t.from, t.to)
+	}
+}
@@ -0,0 +1,148 @@
+package stream_chat // nolint: golint
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+
+	var failing, calls int32 = 1, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if atomic.LoadInt32(&failing) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"code":-1,"message":"unavailable"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"message":{"id":"msg"},"users":{}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	var transitions []CircuitState
+	config := CircuitBreakerConfig{
+		FailureRate: 0.5,
+		MinRequests: 4,
+		Window:      time.Minute,
+		Cooldown:    50 * time.Millisecond,
+		OnStateChange: func(group string, from, to CircuitState) {
+			require.Equal(t, EndpointGroupMessages, group)
+			transitions = append(transitions, to)
+		},
+	}
+	c, err := NewClient("key", "secret", WithBaseURL(srv.URL), WithCircuitBreaker(config))
+	require.NoError(t, err)
+
+	for i := 0; i < 4; i++ {
+		_, err = c.GetMessage(ctx, "msg")
+		require.False(t, IsCircuitOpen(err))
+	}
+
+	_, err = c.GetMessage(ctx, "msg")
+	require.True(t, IsCircuitOpen(err))
+	var openErr *CircuitOpenError
+	require.True(t, errors.As(err, &openErr))
+	require.Equal(t, EndpointGroupMessages, openErr.Group)
+	require.True(t, openErr.RetryAfter > 0 && openErr.RetryAfter <= config.Cooldown)
+	require.EqualValues(t, 4, atomic.LoadInt32(&calls), "the request is not sent")
+
+	_, err = c.UpdateUser(ctx, &User{ID: "tommaso"})
+	require.False(t, IsCircuitOpen(err), "other endpoint groups are not affected")
+	require.EqualValues(t, 5, atomic.LoadInt32(&calls))
+
+	time.Sleep(config.Cooldown)
+	_, err = c.GetMessage(ctx, "msg")
+	require.False(t, IsCircuitOpen(err), "a probe is sent after the cooldown")
+	_, err = c.GetMessage(ctx, "msg")
+	require.True(t, IsCircuitOpen(err), "the failed probe opens the circuit again")
+
+	atomic.StoreInt32(&failing, 0)
+	time.Sleep(config.Cooldown)
+	_, err = c.GetMessage(ctx, "msg")
+	require.NoError(t, err)
+	_, err = c.GetMessage(ctx, "msg")
+	require.NoError(t, err, "the successful probe closes the circuit")
+
+	require.Equal(t, []CircuitState{CircuitOpen, CircuitHalfOpen, CircuitOpen, CircuitHalfOpen, CircuitClosed}, transitions)
+}
+
+func TestCircuitBreaker_HalfOpenProbes(t *testing.T) {
+	b := newCircuitBreaker(CircuitBreakerConfig{MinRequests: 1, Cooldown: time.Millisecond, HalfOpenProbes: 2},
+		func(string, ...interface{}) {})
+
+	done, err := b.allow("SendMessage")
+	require.NoError(t, err)
+	done(circuitFailure)
+	time.Sleep(time.Millisecond)
+
+	probe1, err := b.allow("SendMessage")
+	require.NoError(t, err)
+	probe2, err := b.allow("SendMessage")
+	require.NoError(t, err)
+	_, err = b.allow("SendMessage")
+	require.True(t, IsCircuitOpen(err), "only HalfOpenProbes requests are let through")
+
+	probe1(circuitIgnored)
+	probe3, err := b.allow("SendMessage")
+	require.NoError(t, err, "an ignored probe frees its slot")
+
+	probe2(circuitSuccess)
+	probe3(circuitSuccess)
+	_, err = b.allow("SendMessage")
+	require.NoError(t, err)
+	require.Equal(t, CircuitClosed, b.circuits[EndpointGroupMessages].state)
+}
+
+func TestCircuitBreaker_OnStateChangeUsesClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(srv.Close)
+
+	var c *Client
+	var states []CircuitState
+	config := CircuitBreakerConfig{
+		MinRequests: 1,
+		Cooldown:    time.Minute,
+		OnStateChange: func(group string, from, to CircuitState) {
+			// the breaker is not locked while the hook runs
+			_, err := c.GetMessage(context.Background(), "msg")
+			require.True(t, IsCircuitOpen(err))
+			states = append(states, to)
+		},
+	}
+	c, err := NewClient("key", "secret", WithBaseURL(srv.URL), WithCircuitBreaker(config))
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = c.GetMessage(context.Background(), "msg")
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnStateChange deadlocked")
+	}
+	require.Equal(t, []CircuitState{CircuitOpen}, states)
+}
+
+func TestRequestOutcome(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ctx := context.Background()
+	require.Equal(t, circuitSuccess, requestOutcome(ctx, &http.Response{StatusCode: http.StatusNotFound}, nil))
+	require.Equal(t, circuitIgnored, requestOutcome(ctx, &http.Response{StatusCode: http.StatusTooManyRequests}, nil))
+	require.Equal(t, circuitFailure, requestOutcome(ctx, &http.Response{StatusCode: http.StatusBadGateway}, nil))
+	require.Equal(t, circuitFailure, requestOutcome(ctx, nil, errors.New("connection refused")))
+	require.Equal(t, circuitIgnored, requestOutcome(canceled, nil, context.Canceled))
+}
@@ -2,6 +2,7 @@ package stream_chat // nolint: golint
 
 import (
 	"bytes"
+	"context"
 	"crypto"
 	"crypto/hmac"
 	"encoding/hex"
@@ -10,12 +11,12 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
-	"mime/multipart"
 	"net/http"
-	"net/textproto"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
@@ -30,14 +31,36 @@ type Client struct {
 	BaseURL string
 	HTTP    *http.Client `json:"-"`
 
-	apiKey    string
-	apiSecret []byte
-	authToken string
+	apiKey          string
+	apiSecret       []byte
+	previousSecrets [][]byte
+	authToken       string
+
+	retryPolicy   *RetryPolicy
+	breaker       *circuitBreaker
+	failover      *failover
+	onResponse    ResponseCallback
+	middlewares   []Middleware
+	userAgent     string
+	logger        Logger
+	leveledLogger LeveledLogger
+	logLevel      LogLevel
+	metrics       MetricsCollector
+	tracer        Tracer
+	maxUploadSize int64
+
+	compression        bool
+	compressionMinSize int
+
+	// rateLimiter is switched at runtime by EnableRateLimiter and DisableRateLimiter
+	rateLimiterMu sync.RWMutex
+	rateLimiter   *rateLimiter
 }
 
 func (c *Client) setHeaders(r *http.Request) {
 	r.Header.Set("Content-Type", "application/json")
 	r.Header.Set("X-Stream-Client", versionHeader())
+	r.Header.Set("User-Agent", c.userAgent)
 	r.Header.Set("Authorization", c.authToken)
 	r.Header.Set("Stream-Auth-Type", "jwt")
 }
@@ -48,20 +71,23 @@ func (c *Client) parseResponse(resp *http.Response, result interface{}) error {
 	}
 
 	if resp.StatusCode >= 399 {
-		msg, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf("chat-client: HTTP %s %s status %s: %s",
-			resp.Request.Method, resp.Request.URL, resp.Status, string(msg))
+		msg, _ := ioutil.ReadAll(responseBody(resp))
+		return newAPIError(resp, msg)
 	}
 
 	if result != nil {
-		return json.NewDecoder(resp.Body).Decode(result)
+		return json.NewDecoder(responseBody(resp)).Decode(result)
 	}
 
 	return nil
 }
 
 func (c *Client) requestURL(path string, values url.Values) (string, error) {
-	_url, err := url.Parse(c.BaseURL + "/" + path)
+	baseURL := c.BaseURL
+	if c.failover != nil {
+		baseURL = c.failover.base
+	}
+	_url, err := url.Parse(baseURL + "/" + path)
 	if err != nil {
 		return "", errors.New("url.Parse: " + err.Error())
 	}
@@ -77,18 +103,30 @@ func (c *Client) requestURL(path string, values url.Values) (string, error) {
 	return _url.String(), nil
 }
 
-func (c *Client) newRequest(method, path string, params url.Values, data interface{}) (*http.Request, error) {
+func (c *Client) newRequest(ctx context.Context, method, path string, params url.Values, data interface{}) (*http.Request, error) {
 	_url, err := c.requestURL(path, params)
 	if err != nil {
 		return nil, err
 	}
 
-	r, err := http.NewRequest(method, _url, nil)
+	ctx = withEndpoint(ctx, endpointName(method, path))
+	r, err := http.NewRequestWithContext(ctx, method, _url, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	c.setHeaders(r)
+	if c.tracer != nil {
+		c.tracer.Inject(ctx, r.Header)
+	}
+	if key := idempotencyKeyFromContext(ctx); key != "" {
+		r.Header.Set(idempotencyKeyHeader, key)
+	}
+	if c.compression {
+		// set explicitly, the transport does not decompress the response then
+		r.Header.Set("Accept-Encoding", gzipEncoding)
+	}
+
 	switch t := data.(type) {
 	case nil:
 		r.Body = nil
@@ -104,48 +142,84 @@ func (c *Client) newRequest(method, path string, params url.Values, data interfa
 		if err != nil {
 			return nil, err
 		}
-		r.Body = ioutil.NopCloser(bytes.NewReader(b))
+		if c.compression && len(b) >= c.compressionMinSize {
+			if b, err = gzipBytes(b); err != nil {
+				return nil, err
+			}
+			r.Header.Set("Content-Encoding", gzipEncoding)
+		}
+		// keep the body rewindable, so the request can be retried
+		r.ContentLength = int64(len(b))
+		r.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(b)), nil
+		}
+		r.Body, _ = r.GetBody()
 	}
 
 	return r, nil
 }
 
-func (c *Client) makeRequest(method, path string, params url.Values, data, result interface{}) error {
-	r, err := c.newRequest(method, path, params, data)
-	if err != nil {
-		return err
-	}
+// send performs a single attempt of the request.
+func (c *Client) send(r *http.Request) (*http.Response, error) {
+	endpoint := endpointFromContext(r.Context())
 
-	resp, err := c.HTTP.Do(r)
-	if err != nil {
-		return err
+	limiter := c.loadRateLimiter()
+	if l := limiter; l != nil {
+		if err := l.wait(r.Context(), endpoint); err != nil {
+			return nil, err
+		}
 	}
 
-	return c.parseResponse(resp, result)
-}
-
-// CreateToken creates a new token for user with optional expire time.
-// Zero time is assumed to be no expire.
-func (c *Client) CreateToken(userID string, expire time.Time, issuedAt ...time.Time) (string, error) {
-	if userID == "" {
-		return "", errors.New("user ID is empty")
+	var done func(circuitOutcome)
+	if b := c.breaker; b != nil {
+		var err error
+		if done, err = b.allow(endpoint); err != nil {
+			return nil, err
+		}
 	}
 
-	claims := jwt.MapClaims{
-		"user_id": userID,
+	resp, err := c.roundTripFailover(r)
+	if done != nil {
+		done(requestOutcome(r.Context(), resp, err))
 	}
-	if !expire.IsZero() {
-		claims["exp"] = expire.Unix()
+	if err != nil {
+		return nil, err
+	}
+
+	if rl := NewRateLimitFromHeaders(resp.Header); rl != nil {
+		if l := limiter; l != nil {
+			l.update(endpoint, *rl)
+		}
+		if c.metrics != nil {
+			c.metrics.ObserveRateLimit(endpoint, *rl)
+		}
 	}
-	if len(issuedAt) > 0 {
-		claims["iat"] = issuedAt[0].Unix()
+
+	if c.onResponse != nil {
+		c.onResponse(r.Context(), newResponseMetadata(resp))
 	}
 
-	return c.createToken(claims)
+	return resp, nil
 }
 
-func (c *Client) createToken(claims jwt.Claims) (string, error) {
-	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(c.apiSecret)
+func (c *Client) makeRequest(ctx context.Context, method, path string, params url.Values, data, result interface{}) error {
+	ctx, endSpan := c.startSpan(ctx, method, path, params, data)
+
+	r, err := c.newRequest(ctx, method, path, params, data)
+	if err != nil {
+		endSpan(nil, err)
+		return err
+	}
+
+	resp, err := c.do(r)
+	if err != nil {
+		endSpan(nil, err)
+		return err
+	}
+
+	err = c.parseResponse(resp, result)
+	endSpan(resp, err)
+	return err
 }
 
 // VerifyWebhook validates if hmac signature is correct for message body.
@@ -154,132 +228,164 @@ func (c *Client) VerifyWebhook(body, signature []byte) (valid bool) {
 	_, _ = mac.Write(body)
 
 	expectedMAC := hex.EncodeToString(mac.Sum(nil))
-	return bytes.Equal(signature, []byte(expectedMAC))
+	return hmac.Equal(signature, []byte(expectedMAC))
 }
 
-type sendFileResponse struct {
-	File string `json:"file"`
+// ClientOption configures a Client created with NewClient.
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	baseURL            string
+	baseURLs           []string
+	failbackInterval   time.Duration
+	httpClient         *http.Client
+	timeout            time.Duration
+	timeoutSet         bool
+	userAgentSuffix    string
+	retryPolicy        *RetryPolicy
+	onResponse         ResponseCallback
+	middlewares        []Middleware
+	circuitBreaker     *CircuitBreakerConfig
+	logger             Logger
+	leveledLogger      LeveledLogger
+	logLevel           LogLevel
+	metrics            MetricsCollector
+	tracer             Tracer
+	maxUploadSize      int64
+	compression        bool
+	compressionMinSize int
+	previousSecrets    []string
 }
 
-var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
-
-func escapeQuotes(s string) string {
-	return quoteEscaper.Replace(s)
+// WithBaseURL sets the URL of the API, by default the edge endpoint is used.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(o *clientOptions) {
+		o.baseURL = baseURL
+	}
 }
 
-// this makes possible to set content type.
-type multipartForm struct {
-	*multipart.Writer
+// WithHTTPClient sets the HTTP client used to send requests.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(o *clientOptions) {
+		o.httpClient = httpClient
+	}
 }
 
-// CreateFormFile is a convenience wrapper around CreatePart. It creates
-// a new form-data header with the provided field name, file name and content type.
-func (form *multipartForm) CreateFormFile(fieldName, filename, contentType string) (io.Writer, error) {
-	h := make(textproto.MIMEHeader)
-
-	h.Set("Content-Disposition",
-		fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
-			escapeQuotes(fieldName), escapeQuotes(filename)))
-
-	if contentType == "" {
-		contentType = "application/octet-stream"
+// WithTimeout sets the timeout of every HTTP attempt: each retry gets the whole timeout again.
+// Use a context with a deadline to bound a call with its retries.
+// It overrides the timeout of the client given with WithHTTPClient, without modifying it.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.timeout = timeout
+		o.timeoutSet = true
 	}
-
-	h.Set("Content-Type", contentType)
-
-	return form.Writer.CreatePart(h)
 }
 
-func (form *multipartForm) setData(fieldName string, data interface{}) error {
-	field, err := form.CreateFormField(fieldName)
-	if err != nil {
-		return err
+// WithUserAgentSuffix appends suffix to the User-Agent header of every request,
+// to identify the application using the client.
+func WithUserAgentSuffix(suffix string) ClientOption {
+	return func(o *clientOptions) {
+		o.userAgentSuffix = suffix
 	}
-	return json.NewEncoder(field).Encode(data)
 }
 
-func (form *multipartForm) setFile(fieldName string, r io.Reader, fileName, contentType string) error {
-	file, err := form.CreateFormFile(fieldName, fileName, contentType)
-	if err != nil {
-		return err
+// WithRetryPolicy enables automatic retries of failed requests with the given policy.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(o *clientOptions) {
+		o.retryPolicy = &policy
 	}
-	_, err = io.Copy(file, r)
-
-	return err
 }
 
-func (c *Client) sendFile(link string, opts SendFileRequest) (string, error) {
-	if opts.User == nil {
-		return "", errors.New("user is nil")
+// WithLogger sets the logger used by the client. The fields of the log entries are printed
+// after the message as key=value pairs.
+func WithLogger(logger Logger) ClientOption {
+	return func(o *clientOptions) {
+		o.logger = logger
 	}
+}
 
-	tmpfile, err := ioutil.TempFile("", opts.FileName)
-	if err != nil {
-		return "", err
+// WithMaxUploadSize sets the largest file accepted by SendFile and SendImage, 100 MB by default.
+// Zero disables the check.
+func WithMaxUploadSize(size int64) ClientOption {
+	return func(o *clientOptions) {
+		o.maxUploadSize = size
 	}
+}
 
-	defer func() {
-		_ = tmpfile.Close()
-		_ = os.Remove(tmpfile.Name())
-	}()
-
-	form := multipartForm{multipart.NewWriter(tmpfile)}
-
-	if err := form.setData("user", opts.User); err != nil {
-		return "", err
+// WithPreviousSecrets sets secrets which were used before the current API secret.
+// Tokens signed with them are still accepted by VerifyUserToken and ParseUserToken,
+// which allows rotating the secret without invalidating the tokens already issued.
+func WithPreviousSecrets(secrets ...string) ClientOption {
+	return func(o *clientOptions) {
+		o.previousSecrets = append(o.previousSecrets, secrets...)
 	}
+}
 
-	err = form.setFile("file", opts.Reader, opts.FileName, opts.ContentType)
-	if err != nil {
-		return "", err
+// NewClient creates new stream chat api client.
+func NewClient(apiKey, apiSecret string, options ...ClientOption) (*Client, error) {
+	switch {
+	case apiKey == "":
+		return nil, errors.New("API key is empty")
+	case apiSecret == "":
+		return nil, errors.New("API secret is empty")
 	}
 
-	err = form.Close()
-	if err != nil {
-		return "", err
+	opts := clientOptions{
+		baseURL:       defaultBaseURL,
+		timeout:       defaultTimeout,
+		logLevel:      LogLevelWarn,
+		maxUploadSize: defaultMaxUploadSize,
 	}
-
-	if _, err = tmpfile.Seek(0, 0); err != nil {
-		return "", err
+	for _, opt := range options {
+		opt(&opts)
 	}
 
-	r, err := c.newRequest(http.MethodPost, link, nil, tmpfile)
-	if err != nil {
-		return "", err
+	httpClient := opts.httpClient
+	switch {
+	case httpClient == nil:
+		httpClient = &http.Client{Timeout: opts.timeout}
+	case opts.timeoutSet:
+		hc := *httpClient
+		hc.Timeout = opts.timeout
+		httpClient = &hc
 	}
 
-	r.Header.Set("Content-Type", form.FormDataContentType())
-
-	res, err := c.HTTP.Do(r)
-	if err != nil {
-		return "", err
+	userAgent := versionHeader()
+	if opts.userAgentSuffix != "" {
+		userAgent += " " + opts.userAgentSuffix
 	}
 
-	var resp sendFileResponse
-	err = c.parseResponse(res, &resp)
-	if err != nil {
-		return "", err
+	client := &Client{
+		apiKey:        apiKey,
+		apiSecret:     []byte(apiSecret),
+		BaseURL:       strings.TrimRight(opts.baseURL, "/"),
+		HTTP:          httpClient,
+		userAgent:     userAgent,
+		retryPolicy:   opts.retryPolicy,
+		onResponse:    opts.onResponse,
+		middlewares:   opts.middlewares,
+		logger:        opts.logger,
+		leveledLogger: opts.leveledLogger,
+		logLevel:      opts.logLevel,
+		metrics:       opts.metrics,
+		tracer:        opts.tracer,
+		maxUploadSize: opts.maxUploadSize,
+
+		compression:        opts.compression,
+		compressionMinSize: opts.compressionMinSize,
 	}
-
-	return resp.File, err
-}
-
-// NewClient creates new stream chat api client.
-func NewClient(apiKey, apiSecret string) (*Client, error) {
-	switch {
-	case apiKey == "":
-		return nil, errors.New("API key is empty")
-	case apiSecret == "":
-		return nil, errors.New("API secret is empty")
+	if len(opts.baseURLs) > 1 {
+		f, err := newFailover(opts.baseURLs, opts.failbackInterval)
+		if err != nil {
+			return nil, err
+		}
+		client.failover = f
 	}
-
-	client := &Client{
-		apiKey:    apiKey,
-		apiSecret: []byte(apiSecret),
-		BaseURL:   defaultBaseURL,
-		HTTP: &http.Client{
-			Timeout: defaultTimeout,
-		},
+	if opts.circuitBreaker != nil {
+		client.breaker = newCircuitBreaker(*opts.circuitBreaker, client.logf)
+	}
+	for _, secret := range opts.previousSecrets {
+		client.previousSecrets = append(client.previousSecrets, []byte(secret))
 	}
 
 	token, err := client.createToken(jwt.MapClaims{"server": true})
@@ -292,6 +398,41 @@ func NewClient(apiKey, apiSecret string) (*Client, error) {
 	return client, nil
 }
 
+// NewClientFromEnvVars creates a new client with the credentials and settings read from the
+// environment:
+//
+//	STREAM_CHAT_API_KEY: API key, required
+//	STREAM_CHAT_API_SECRET: API secret, required
+//	STREAM_CHAT_API_HOST: URL of the API, optional
+//	STREAM_CHAT_TIMEOUT: request timeout, either a duration like "10s" or a number of seconds, optional
+//
+// Options are applied after the environment settings and take precedence over them.
+func NewClientFromEnvVars(options ...ClientOption) (*Client, error) {
+	var envOptions []ClientOption
+
+	if host := os.Getenv("STREAM_CHAT_API_HOST"); host != "" {
+		envOptions = append(envOptions, WithBaseURL(host))
+	}
+
+	if v := os.Getenv("STREAM_CHAT_TIMEOUT"); v != "" {
+		timeout, err := parseTimeout(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid STREAM_CHAT_TIMEOUT: %w", err)
+		}
+		envOptions = append(envOptions, WithTimeout(timeout))
+	}
+
+	return NewClient(os.Getenv("STREAM_CHAT_API_KEY"), os.Getenv("STREAM_CHAT_API_SECRET"),
+		append(envOptions, options...)...)
+}
+
+func parseTimeout(v string) (time.Duration, error) {
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second, nil
+	}
+	return time.ParseDuration(v)
+}
+
 // Channel prepares a Channel object for future API calls. This does not in and
 // of itself call the API.
 func (c *Client) Channel(channelType, channelID string) *Channel {
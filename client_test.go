@@ -1,7 +1,14 @@
 package stream_chat // nolint: golint
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"testing"
 	"time"
 
@@ -10,20 +17,17 @@ import (
 )
 
 func initClient(t *testing.T) *Client {
-	c, err := NewClient(APIKey, APISecret)
+	c, err := NewClientFromEnvVars()
 	require.NoError(t, err, "new client")
 
-	// set hostname to client from env if present
-	if StreamHost != "" {
-		c.BaseURL = StreamHost
-	}
-
 	return c
 }
 
 func initChannel(t *testing.T, c *Client, membersID ...string) *Channel {
+	ctx := context.Background()
+
 	owner := randomUser(t, c)
-	ch, err := c.CreateChannel("team", randomString(12), owner.ID, map[string]interface{}{
+	ch, err := c.CreateChannel(ctx, "team", randomString(12), owner.ID, map[string]interface{}{
 		"members": membersID,
 	})
 
@@ -40,6 +44,90 @@ func TestNewClient(t *testing.T) {
 	assert.Equal(t, defaultTimeout, c.HTTP.Timeout)
 }
 
+func TestNewClient_Options(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		c, err := NewClient("key", "secret")
+		require.NoError(t, err)
+
+		assert.Equal(t, defaultBaseURL, c.BaseURL)
+		assert.Equal(t, defaultTimeout, c.HTTP.Timeout)
+		assert.Equal(t, versionHeader(), c.userAgent)
+		assert.Nil(t, c.retryPolicy)
+	})
+
+	t.Run("options", func(t *testing.T) {
+		httpClient := &http.Client{Timeout: time.Second}
+		policy := DefaultRetryPolicy()
+		logger := log.New(ioutil.Discard, "", 0)
+
+		c, err := NewClient("key", "secret",
+			WithBaseURL("http://localhost:3030/"),
+			WithHTTPClient(httpClient),
+			WithTimeout(2*time.Second),
+			WithUserAgentSuffix("my-app/1.0"),
+			WithRetryPolicy(policy),
+			WithLogger(logger),
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, "http://localhost:3030", c.BaseURL)
+		assert.Equal(t, 2*time.Second, c.HTTP.Timeout)
+		assert.Equal(t, time.Second, httpClient.Timeout, "given client must not be modified")
+		assert.Equal(t, versionHeader()+" my-app/1.0", c.userAgent)
+		assert.Equal(t, &policy, c.retryPolicy)
+		assert.Equal(t, logger, c.logger)
+	})
+
+	t.Run("http client", func(t *testing.T) {
+		httpClient := &http.Client{Timeout: time.Second}
+
+		c, err := NewClient("key", "secret", WithHTTPClient(httpClient))
+		require.NoError(t, err)
+		assert.Same(t, httpClient, c.HTTP)
+	})
+}
+
+func TestNewClientFromEnvVars(t *testing.T) {
+	setEnv := func(t *testing.T, key, value string) {
+		old, ok := os.LookupEnv(key)
+		require.NoError(t, os.Setenv(key, value))
+		t.Cleanup(func() {
+			if ok {
+				_ = os.Setenv(key, old)
+			} else {
+				_ = os.Unsetenv(key)
+			}
+		})
+	}
+
+	setEnv(t, "STREAM_CHAT_API_KEY", "env-key")
+	setEnv(t, "STREAM_CHAT_API_SECRET", "env-secret")
+	setEnv(t, "STREAM_CHAT_API_HOST", "http://localhost:3030")
+	setEnv(t, "STREAM_CHAT_TIMEOUT", "10")
+
+	c, err := NewClientFromEnvVars()
+	require.NoError(t, err)
+	assert.Equal(t, "env-key", c.apiKey)
+	assert.Equal(t, "env-secret", string(c.apiSecret))
+	assert.Equal(t, "http://localhost:3030", c.BaseURL)
+	assert.Equal(t, 10*time.Second, c.HTTP.Timeout)
+
+	setEnv(t, "STREAM_CHAT_TIMEOUT", "1500ms")
+	c, err = NewClientFromEnvVars(WithBaseURL("http://localhost:4040"))
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:4040", c.BaseURL)
+	assert.Equal(t, 1500*time.Millisecond, c.HTTP.Timeout)
+
+	setEnv(t, "STREAM_CHAT_TIMEOUT", "soon")
+	_, err = NewClientFromEnvVars()
+	require.Error(t, err)
+
+	setEnv(t, "STREAM_CHAT_TIMEOUT", "")
+	setEnv(t, "STREAM_CHAT_API_KEY", "")
+	_, err = NewClientFromEnvVars()
+	require.EqualError(t, err, "API key is empty")
+}
+
 //nolint: lll
 func TestClient_CreateToken(t *testing.T) {
 	type args struct {
@@ -83,7 +171,35 @@ func TestClient_CreateToken(t *testing.T) {
 	}
 }
 
+func TestClient_RequestContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("key", "secret", WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := c.GetAppConfig(ctx)
+		require.True(t, errors.Is(err, context.Canceled), err)
+	})
+
+	t.Run("deadline exceeded", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := c.GetAppConfig(ctx)
+		require.True(t, errors.Is(err, context.DeadlineExceeded), err)
+	})
+}
+
 func TestSendUserCustomEvent(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 
 	tests := []struct {
@@ -116,11 +232,11 @@ func TestSendUserCustomEvent(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			if test.expectedErr == "" {
-				_, err := c.UpsertUser(&User{ID: test.targetUserID})
+				_, err := c.UpsertUser(ctx, &User{ID: test.targetUserID})
 				require.NoError(t, err)
 			}
 
-			err := c.SendUserCustomEvent(test.targetUserID, test.event)
+			err := c.SendUserCustomEvent(ctx, test.targetUserID, test.event)
 
 			if test.expectedErr == "" {
 				require.NoError(t, err)
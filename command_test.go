@@ -1,6 +1,7 @@
 package stream_chat // nolint: golint
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -8,26 +9,30 @@ import (
 )
 
 func prepareCommand(t *testing.T, c *Client) *Command {
+	ctx := context.Background()
+
 	cmd := &Command{
 		Name:        randomString(10),
 		Description: "test command",
 	}
 
-	cmd, err := c.CreateCommand(cmd)
+	cmd, err := c.CreateCommand(ctx, cmd)
 	require.NoError(t, err, "create command")
 
 	return cmd
 }
 
 func TestClient_GetCommand(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 
 	cmd := prepareCommand(t, c)
 	defer func() {
-		_ = c.DeleteCommand(cmd.Name)
+		_ = c.DeleteCommand(ctx, cmd.Name)
 	}()
 
-	got, err := c.GetCommand(cmd.Name)
+	got, err := c.GetCommand(ctx, cmd.Name)
 	require.NoError(t, err, "get command")
 
 	assert.Equal(t, cmd.Name, got.Name)
@@ -35,28 +40,32 @@ func TestClient_GetCommand(t *testing.T) {
 }
 
 func TestClient_ListCommands(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 
 	cmd := prepareCommand(t, c)
 	defer func() {
-		_ = c.DeleteCommand(cmd.Name)
+		_ = c.DeleteCommand(ctx, cmd.Name)
 	}()
 
-	got, err := c.ListCommands()
+	got, err := c.ListCommands(ctx)
 	require.NoError(t, err, "list commands")
 
 	assert.Contains(t, got, cmd)
 }
 
 func TestClient_UpdateCommand(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 
 	cmd := prepareCommand(t, c)
 	defer func() {
-		_ = c.DeleteCommand(cmd.Name)
+		_ = c.DeleteCommand(ctx, cmd.Name)
 	}()
 
-	got, err := c.UpdateCommand(cmd.Name, map[string]interface{}{
+	got, err := c.UpdateCommand(ctx, cmd.Name, map[string]interface{}{
 		"description": "new description",
 	})
 	require.NoError(t, err, "update command")
@@ -67,6 +76,8 @@ func TestClient_UpdateCommand(t *testing.T) {
 
 // See https://getstream.io/chat/docs/custom_commands/ for more details.
 func ExampleClient_CreateCommand() {
+	ctx := context.Background()
+
 	client := &Client{}
 
 	newCommand := &Command{
@@ -76,29 +87,37 @@ func ExampleClient_CreateCommand() {
 		Set:         "custom_cmd_set",
 	}
 
-	_, _ = client.CreateCommand(newCommand)
+	_, _ = client.CreateCommand(ctx, newCommand)
 }
 
 func ExampleClient_ListCommands() {
+	ctx := context.Background()
+
 	client := &Client{}
-	_, _ = client.ListCommands()
+	_, _ = client.ListCommands(ctx)
 }
 
 func ExampleClient_GetCommand() {
+	ctx := context.Background()
+
 	client := &Client{}
-	_, _ = client.GetCommand("my-command")
+	_, _ = client.GetCommand(ctx, "my-command")
 }
 
 func ExampleClient_UpdateCommand() {
+	ctx := context.Background()
+
 	client := &Client{}
 
-	_, _ = client.UpdateCommand("my-command", map[string]interface{}{
+	_, _ = client.UpdateCommand(ctx, "my-command", map[string]interface{}{
 		"description": "updated description",
 	})
 }
 
 func ExampleClient_DeleteCommand() {
+	ctx := context.Background()
+
 	client := &Client{}
 
-	_ = client.DeleteCommand("my-command")
+	_ = client.DeleteCommand(ctx, "my-command")
 }
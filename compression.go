@@ -0,0 +1,91 @@
+package stream_chat // nolint: golint
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+const gzipEncoding = "gzip"
+
+// WithCompression enables gzip compression of the requests and responses.
+//
+// JSON request bodies of at least minSize bytes are compressed, as well as the files sent with
+// SendFile which are not compressed already, like text, JSON or CSV files. Images are sent as
+// they are. Responses are requested gzip encoded and decompressed while they are decoded.
+func WithCompression(minSize int) ClientOption {
+	return func(o *clientOptions) {
+		o.compression = true
+		o.compressionMinSize = minSize
+	}
+}
+
+// gzipBytes returns b compressed with gzip.
+func gzipBytes(b []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(b); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func isGzipped(h http.Header) bool {
+	return strings.EqualFold(h.Get("Content-Encoding"), gzipEncoding)
+}
+
+// responseBody returns the body of resp, decompressed as it is read if it is gzip encoded.
+func responseBody(resp *http.Response) io.Reader {
+	if !isGzipped(resp.Header) {
+		return resp.Body
+	}
+	zr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return errReader{err}
+	}
+	return zr
+}
+
+// gunzipForLog returns the decompressed body if it is gzip encoded, for logging.
+func gunzipForLog(h http.Header, b []byte) []byte {
+	if !isGzipped(h) {
+		return b
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return b
+	}
+	decompressed, err := ioutil.ReadAll(zr)
+	if err != nil {
+		return b
+	}
+	return decompressed
+}
+
+// compressible reports whether files of the content type are worth compressing.
+func compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	if strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml") {
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/xml", "application/javascript", "application/x-ndjson":
+		return true
+	}
+	return false
+}
+
+// compressUpload reports whether an upload of size bytes, -1 if unknown, should be compressed.
+func (c *Client) compressUpload(contentType string, size int64, image bool) bool {
+	return c.compression && !image && compressible(contentType) && (size < 0 || size >= int64(c.compressionMinSize))
+}
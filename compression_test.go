@@ -0,0 +1,110 @@
+package stream_chat // nolint: golint
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type compressedRequest struct {
+	contentEncoding string
+	contentLength   int64
+	body            string
+}
+
+// newCompressionTestServer returns a server gzip encoding its responses and recording the
+// decompressed requests.
+func newCompressionTestServer(t *testing.T) (*httptest.Server, *[]compressedRequest) {
+	var requests []compressedRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := io.Reader(r.Body)
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			body = zr
+		}
+		b, _ := ioutil.ReadAll(body)
+		requests = append(requests, compressedRequest{
+			contentEncoding: r.Header.Get("Content-Encoding"),
+			contentLength:   r.ContentLength,
+			body:            string(b),
+		})
+
+		resp := `{"users":{"tommaso":{"id":"tommaso"}},"file":"https://cdn/file"}`
+		status := http.StatusOK
+		if r.Method == http.MethodGet {
+			resp, status = `{"code":16,"message":"message does not exist"}`, http.StatusNotFound
+		}
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(resp))
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(status)
+		zw := gzip.NewWriter(w)
+		_, _ = zw.Write([]byte(resp))
+		_ = zw.Close()
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestClient_Compression(t *testing.T) {
+	ctx := context.Background()
+	srv, requests := newCompressionTestServer(t)
+
+	c, err := NewClient("key", "secret", WithBaseURL(srv.URL), WithCompression(100))
+	require.NoError(t, err)
+
+	large := &User{ID: "tommaso", Name: strings.Repeat("a", 100)}
+	user, err := c.UpsertUser(ctx, large)
+	require.NoError(t, err)
+	require.Equal(t, "tommaso", user.ID, "the response is decompressed")
+	require.Equal(t, "gzip", (*requests)[0].contentEncoding)
+	require.Contains(t, (*requests)[0].body, large.Name)
+
+	_, err = c.UpsertUser(ctx, &User{ID: "tommaso"})
+	require.NoError(t, err)
+	require.Empty(t, (*requests)[1].contentEncoding, "small bodies are not compressed")
+
+	_, err = c.GetMessage(ctx, "missing")
+	require.True(t, IsNotFound(err), "error responses are decompressed")
+
+	ch := c.Channel("messaging", "general")
+	text := strings.Repeat("hello,world\n", 100)
+	_, err = ch.SendFile(ctx, SendFileRequest{Reader: strings.NewReader(text), FileName: "data.csv", User: large})
+	require.NoError(t, err)
+	upload := (*requests)[3]
+	require.Equal(t, "gzip", upload.contentEncoding)
+	require.EqualValues(t, -1, upload.contentLength)
+	require.Contains(t, upload.body, text)
+
+	png := []byte("\x89PNG\x0D\x0A\x1A\x0A" + strings.Repeat("\x00", 200))
+	_, err = ch.SendImage(ctx, SendFileRequest{Reader: bytes.NewReader(png), FileName: "image.png", User: large})
+	require.NoError(t, err)
+	require.Empty(t, (*requests)[4].contentEncoding, "images are not compressed")
+}
+
+func TestClient_CompressionDisabled(t *testing.T) {
+	srv, requests := newCompressionTestServer(t)
+
+	c, err := NewClient("key", "secret", WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	user, err := c.UpsertUser(context.Background(), &User{ID: "tommaso", Name: strings.Repeat("a", 1000)})
+	require.NoError(t, err)
+	require.Equal(t, "tommaso", user.ID)
+	require.Empty(t, (*requests)[0].contentEncoding)
+}
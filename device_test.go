@@ -1,6 +1,7 @@
 package stream_chat // nolint: golint
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -8,6 +9,8 @@ import (
 )
 
 func TestClient_Devices(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 
 	user := randomUser(t, c)
@@ -18,12 +21,12 @@ func TestClient_Devices(t *testing.T) {
 	}
 
 	for _, dev := range devices {
-		require.NoError(t, c.AddDevice(dev), "add device")
+		require.NoError(t, c.AddDevice(ctx, dev), "add device")
 		defer func(dev *Device) {
-			require.NoError(t, c.DeleteDevice(user.ID, dev.ID), "delete device")
+			require.NoError(t, c.DeleteDevice(ctx, user.ID, dev.ID), "delete device")
 		}(dev)
 
-		resp, err := c.GetDevices(user.ID)
+		resp, err := c.GetDevices(ctx, user.ID)
 		require.NoError(t, err, "get devices")
 
 		assert.True(t, deviceIDExists(resp, dev.ID), "device with ID %s was created", dev.ID)
@@ -40,9 +43,11 @@ func deviceIDExists(dev []*Device, id string) bool {
 }
 
 func ExampleClient_AddDevice() {
+	ctx := context.Background()
+
 	client, _ := NewClient("XXXX", "XXXX")
 
-	_ = client.AddDevice(&Device{
+	_ = client.AddDevice(ctx, &Device{
 		ID:           "2ffca4ad6599adc9b5202d15a5286d33c19547d472cd09de44219cda5ac30207",
 		UserID:       "elon",
 		PushProvider: PushProviderAPNS,
@@ -50,9 +55,11 @@ func ExampleClient_AddDevice() {
 }
 
 func ExampleClient_DeleteDevice() {
+	ctx := context.Background()
+
 	client, _ := NewClient("XXXX", "XXXX")
 
 	deviceID := "2ffca4ad6599adc9b5202d15a5286d33c19547d472cd09de44219cda5ac30207"
 	userID := "elon"
-	_ = client.DeleteDevice(userID, deviceID)
+	_ = client.DeleteDevice(ctx, userID, deviceID)
 }
@@ -0,0 +1,135 @@
+package stream_chat // nolint: golint
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// endpointRoute maps a request method and path pattern to the name the API uses for the endpoint,
+// for example in the rate limits. A "*" in the pattern matches any single path segment.
+type endpointRoute struct {
+	method  string
+	pattern string
+	name    string
+}
+
+//nolint: gochecknoglobals
+var endpointRoutes = []endpointRoute{
+	{http.MethodGet, "app", "GetApp"},
+	{http.MethodPatch, "app", "UpdateApp"},
+	{http.MethodGet, "rate_limits", "GetRateLimits"},
+
+	{http.MethodGet, "tasks/*", "GetTask"},
+	{http.MethodPost, "export_channels", "ExportChannels"},
+	{http.MethodGet, "export_channels/*", "GetExportChannelsStatus"},
+
+	{http.MethodPost, "channels", "QueryChannels"},
+	{http.MethodPost, "channels/read", "MarkChannelsRead"},
+	{http.MethodPost, "channels/delete", "DeleteChannels"},
+	{http.MethodPost, "channels/*/query", "GetOrCreateChannel"},
+	{http.MethodPost, "channels/*/*/query", "GetOrCreateChannel"},
+	{http.MethodPost, "channels/*/*", "UpdateChannel"},
+	{http.MethodPatch, "channels/*/*", "UpdateChannelPartial"},
+	{http.MethodDelete, "channels/*/*", "DeleteChannel"},
+	{http.MethodPost, "channels/*/*/truncate", "TruncateChannel"},
+	{http.MethodPost, "channels/*/*/import", "ImportChannelMessages"},
+	{http.MethodPost, "channels/*/*/read", "MarkRead"},
+	{http.MethodPost, "channels/*/*/show", "ShowChannel"},
+	{http.MethodPost, "channels/*/*/hide", "HideChannel"},
+	{http.MethodPost, "channels/*/*/event", "SendEvent"},
+	{http.MethodPost, "channels/*/*/message", "SendMessage"},
+	{http.MethodPost, "channels/*/*/file", "UploadFile"},
+	{http.MethodDelete, "channels/*/*/file", "DeleteFile"},
+	{http.MethodPost, "channels/*/*/image", "UploadImage"},
+	{http.MethodDelete, "channels/*/*/image", "DeleteImage"},
+	{http.MethodGet, "members", "QueryMembers"},
+
+	{http.MethodGet, "messages/*", "GetMessage"},
+	{http.MethodPost, "messages/*", "UpdateMessage"},
+	{http.MethodPut, "messages/*", "UpdateMessagePartial"},
+	{http.MethodDelete, "messages/*", "DeleteMessage"},
+	{http.MethodGet, "messages/*/replies", "GetReplies"},
+	{http.MethodPost, "messages/*/action", "RunMessageAction"},
+	{http.MethodPost, "messages/*/reaction", "SendReaction"},
+	{http.MethodDelete, "messages/*/reaction/*", "DeleteReaction"},
+	{http.MethodGet, "messages/*/reactions", "GetReactions"},
+	{http.MethodGet, "search", "Search"},
+
+	{http.MethodGet, "users", "QueryUsers"},
+	{http.MethodPost, "users", "UpdateUsers"},
+	{http.MethodPatch, "users", "UpdateUsersPartial"},
+	{http.MethodPost, "users/delete", "DeleteUsers"},
+	{http.MethodDelete, "users/*", "DeleteUser"},
+	{http.MethodGet, "users/*/export", "ExportUser"},
+	{http.MethodPost, "users/*/deactivate", "DeactivateUser"},
+	{http.MethodPost, "users/*/reactivate", "ReactivateUser"},
+	{http.MethodPost, "users/*/event", "SendUserCustomEvent"},
+
+	{http.MethodPost, "moderation/mute", "MuteUser"},
+	{http.MethodPost, "moderation/unmute", "UnmuteUser"},
+	{http.MethodPost, "moderation/mute/channel", "MuteChannel"},
+	{http.MethodPost, "moderation/unmute/channel", "UnmuteChannel"},
+	{http.MethodPost, "moderation/flag", "Flag"},
+	{http.MethodPost, "moderation/unflag", "Unflag"},
+	{http.MethodPost, "moderation/ban", "Ban"},
+	{http.MethodDelete, "moderation/ban", "Unban"},
+	{http.MethodGet, "moderation/flags/message", "QueryMessageFlags"},
+
+	{http.MethodGet, "channeltypes", "ListChannelTypes"},
+	{http.MethodPost, "channeltypes", "CreateChannelType"},
+	{http.MethodGet, "channeltypes/*", "GetChannelType"},
+	{http.MethodPut, "channeltypes/*", "UpdateChannelType"},
+	{http.MethodDelete, "channeltypes/*", "DeleteChannelType"},
+
+	{http.MethodGet, "commands", "ListCommands"},
+	{http.MethodPost, "commands", "CreateCommand"},
+	{http.MethodGet, "commands/*", "GetCommand"},
+	{http.MethodPut, "commands/*", "UpdateCommand"},
+	{http.MethodDelete, "commands/*", "DeleteCommand"},
+
+	{http.MethodGet, "devices", "ListDevices"},
+	{http.MethodPost, "devices", "CreateDevice"},
+	{http.MethodDelete, "devices", "DeleteDevice"},
+}
+
+func (e endpointRoute) match(method string, segments []string) bool {
+	if e.method != method {
+		return false
+	}
+
+	pattern := strings.Split(e.pattern, "/")
+	if len(pattern) != len(segments) {
+		return false
+	}
+	for i, p := range pattern {
+		if p != "*" && p != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// endpointName returns the API name of the endpoint serving the request,
+// or an empty string if it is unknown.
+func endpointName(method, path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for _, route := range endpointRoutes {
+		if route.match(method, segments) {
+			return route.name
+		}
+	}
+	return ""
+}
+
+type endpointCtxKey struct{}
+
+func withEndpoint(ctx context.Context, endpoint string) context.Context {
+	return context.WithValue(ctx, endpointCtxKey{}, endpoint)
+}
+
+// endpointFromContext returns the endpoint name stored in the request context by newRequest.
+func endpointFromContext(ctx context.Context) string {
+	endpoint, _ := ctx.Value(endpointCtxKey{}).(string)
+	return endpoint
+}
@@ -0,0 +1,32 @@
+package stream_chat // nolint: golint
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointName(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{http.MethodPost, "channels", "QueryChannels"},
+		{http.MethodPost, "channels/messaging/general/message", "SendMessage"},
+		{http.MethodPost, "channels/messaging/general", "UpdateChannel"},
+		{http.MethodDelete, "channels/messaging/general", "DeleteChannel"},
+		{http.MethodPost, "channels/messaging/query", "GetOrCreateChannel"},
+		{http.MethodDelete, "messages/123/reaction/love", "DeleteReaction"},
+		{http.MethodPost, "moderation/mute/channel", "MuteChannel"},
+		{http.MethodPost, "users", "UpdateUsers"},
+		{http.MethodGet, "rate_limits", "GetRateLimits"},
+		{http.MethodGet, "unknown/path", ""},
+		{http.MethodPatch, "channels/messaging/general/message", ""},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.want, endpointName(tt.method, tt.path), "%s %s", tt.method, tt.path)
+	}
+}
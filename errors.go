@@ -0,0 +1,103 @@
+package stream_chat // nolint: golint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Error codes returned by the Stream API in the code field of an error response.
+// See https://getstream.io/chat/docs/go-golang/api_errors_response/ for the full list.
+const (
+	ErrorCodeInternal                = -1
+	ErrorCodeAccessKey               = 2
+	ErrorCodeAuthenticationFailed    = 3
+	ErrorCodeInput                   = 4
+	ErrorCodeRateLimit               = 9
+	ErrorCodeDoesNotExist            = 16
+	ErrorCodeNotAllowed              = 17
+	ErrorCodeMessageTooLong          = 20
+	ErrorCodePayloadTooBig           = 22
+	ErrorCodeRequestTimeout          = 23
+	ErrorCodeTokenExpired            = 40
+	ErrorCodeTokenNotValidYet        = 41
+	ErrorCodeTokenUsedBeforeIssuedAt = 42
+	ErrorCodeTokenSignatureInvalid   = 43
+	ErrorCodeCoolDown                = 60
+	ErrorCodeAppSuspended            = 99
+)
+
+// APIError is the error returned for every non successful response of the Stream API.
+// Use errors.As to get it from the error returned by Client and Channel methods.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int `json:"StatusCode"`
+	// Code is the Stream error code, one of the ErrorCode* constants.
+	Code int `json:"code"`
+	// Message is the human readable description of the error.
+	Message string `json:"message"`
+	// ExceptionFields holds the details for input errors, keyed by field name.
+	ExceptionFields map[string]string `json:"exception_fields,omitempty"`
+	// MoreInfo is a link to the documentation of the error.
+	MoreInfo string `json:"more_info,omitempty"`
+	// Duration is the time the server spent on the request.
+	Duration string `json:"duration,omitempty"`
+
+	// RateLimit holds the rate limit headers of the response, if present.
+	RateLimit *RateLimitInfo `json:"-"`
+	// RequestID identifies the request on the server side, if returned.
+	RequestID string `json:"-"`
+}
+
+// Error implements error.
+func (e *APIError) Error() string {
+	return fmt.Sprintf("chat-client: HTTP status %d, code %d: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// newAPIError builds the APIError for a non successful response.
+// Bodies which cannot be decoded are kept as the error message.
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{}
+	if err := json.Unmarshal(body, apiErr); err != nil || apiErr.Message == "" {
+		apiErr.Message = string(body)
+	}
+
+	apiErr.StatusCode = resp.StatusCode
+	apiErr.RateLimit = NewRateLimitFromHeaders(resp.Header)
+	apiErr.RequestID = resp.Header.Get(requestIDHeader)
+
+	return apiErr
+}
+
+func asAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+// IsNotFound reports whether err is an APIError for a resource that does not exist.
+func IsNotFound(err error) bool {
+	apiErr, ok := asAPIError(err)
+	return ok && (apiErr.StatusCode == http.StatusNotFound || apiErr.Code == ErrorCodeDoesNotExist)
+}
+
+// IsRateLimited reports whether err is an APIError caused by exceeding a rate limit.
+func IsRateLimited(err error) bool {
+	apiErr, ok := asAPIError(err)
+	return ok && (apiErr.StatusCode == http.StatusTooManyRequests || apiErr.Code == ErrorCodeRateLimit)
+}
+
+// IsPermissionDenied reports whether err is an APIError caused by missing permissions.
+func IsPermissionDenied(err error) bool {
+	apiErr, ok := asAPIError(err)
+	return ok && (apiErr.StatusCode == http.StatusForbidden || apiErr.Code == ErrorCodeNotAllowed)
+}
+
+// IsInputError reports whether err is an APIError caused by invalid request parameters.
+func IsInputError(err error) bool {
+	apiErr, ok := asAPIError(err)
+	return ok && apiErr.Code == ErrorCodeInput
+}
@@ -0,0 +1,79 @@
+package stream_chat // nolint: golint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_APIError(t *testing.T) {
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "60")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "1609459200")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"code":9,"message":"Too many requests","StatusCode":429,` +
+			`"exception_fields":{"user_id":"throttled"},"more_info":"https://getstream.io/chat/docs/rate_limits"}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("key", "secret", WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	_, err = c.DeleteUsers(ctx, []string{"user"}, DeleteUserOptions{User: SoftDelete})
+	require.Error(t, err)
+
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+	require.Equal(t, ErrorCodeRateLimit, apiErr.Code)
+	require.Equal(t, "Too many requests", apiErr.Message)
+	require.Equal(t, map[string]string{"user_id": "throttled"}, apiErr.ExceptionFields)
+	require.Equal(t, "https://getstream.io/chat/docs/rate_limits", apiErr.MoreInfo)
+	require.Equal(t, &RateLimitInfo{Limit: 60, Remaining: 0, Reset: 1609459200}, apiErr.RateLimit)
+
+	require.True(t, IsRateLimited(err))
+	require.False(t, IsNotFound(err))
+}
+
+func TestAPIError_Helpers(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		is   func(error) bool
+		want bool
+	}{
+		{"not found by status", &APIError{StatusCode: http.StatusNotFound}, IsNotFound, true},
+		{"not found by code", &APIError{StatusCode: http.StatusBadRequest, Code: ErrorCodeDoesNotExist}, IsNotFound, true},
+		{"not found wrapped", fmt.Errorf("wrapped: %w", &APIError{StatusCode: http.StatusNotFound}), IsNotFound, true},
+		{"not found plain error", errors.New("not found"), IsNotFound, false},
+		{"rate limited", &APIError{StatusCode: http.StatusTooManyRequests}, IsRateLimited, true},
+		{"permission denied", &APIError{StatusCode: http.StatusForbidden, Code: ErrorCodeNotAllowed}, IsPermissionDenied, true},
+		{"input error", &APIError{StatusCode: http.StatusBadRequest, Code: ErrorCodeInput}, IsInputError, true},
+		{"input error mismatch", &APIError{StatusCode: http.StatusBadRequest, Code: ErrorCodeNotAllowed}, IsInputError, false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.is(tt.err))
+		})
+	}
+}
+
+func TestNewAPIError_NonJSONBody(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusBadGateway, Header: http.Header{}}
+
+	apiErr := newAPIError(resp, []byte("bad gateway"))
+	require.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+	require.Equal(t, "bad gateway", apiErr.Message)
+	require.Nil(t, apiErr.RateLimit)
+	require.Equal(t, "chat-client: HTTP status 502, code 0: bad gateway", apiErr.Error())
+}
@@ -0,0 +1,284 @@
+package stream_chat // nolint: golint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultFailbackInterval = 30 * time.Second
+
+// WithBaseURLs sets several URLs of the API, like regional edges, in order of preference.
+//
+// A base URL is unhealthy for the failback interval after a connection failure or a server
+// error, and the requests are sent to the next healthy one meanwhile. Idempotent requests fail
+// over to the next base URL right away, like the ones with an idempotency key when the
+// RetryPolicy has RetryIdempotencyKeyed set. Once the interval elapsed, the requests go back to
+// the preferred base URL. NewClient fails if one of them is not an absolute URL.
+func WithBaseURLs(baseURLs ...string) ClientOption {
+	return func(o *clientOptions) {
+		if len(baseURLs) > 0 {
+			o.baseURL = baseURLs[0]
+		}
+		o.baseURLs = baseURLs
+	}
+}
+
+// WithFailbackInterval sets how long a base URL set with WithBaseURLs is skipped after a failure,
+// 30 seconds by default.
+func WithFailbackInterval(interval time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.failbackInterval = interval
+	}
+}
+
+type baseURLState struct {
+	url      string
+	failedAt time.Time
+}
+
+// failover tracks the health of the base URLs.
+type failover struct {
+	interval time.Duration
+	// base is the URL the requests are built with, before being sent to a base URL
+	base string
+	// parsed holds the parsed base URLs, read without lock
+	parsed map[string]*url.URL
+
+	mu   sync.Mutex
+	urls []*baseURLState // in order of preference
+}
+
+func newFailover(baseURLs []string, interval time.Duration) (*failover, error) {
+	if interval <= 0 {
+		interval = defaultFailbackInterval
+	}
+	f := &failover{
+		interval: interval,
+		base:     strings.TrimRight(baseURLs[0], "/"),
+		parsed:   make(map[string]*url.URL, len(baseURLs)),
+	}
+	for _, u := range baseURLs {
+		u = strings.TrimRight(u, "/")
+		parsed, err := url.Parse(u)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base URL %s: %w", u, err)
+		}
+		if parsed.Scheme == "" || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid base URL %s: scheme or host missing", u)
+		}
+		f.parsed[u] = parsed
+		f.urls = append(f.urls, &baseURLState{url: u})
+	}
+	return f, nil
+}
+
+// candidates returns the healthy base URLs in order of preference,
+// followed by the unhealthy ones, the least recently failed first.
+func (f *failover) candidates() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	var healthy []string
+	var unhealthy []*baseURLState
+	for _, u := range f.urls {
+		if u.failedAt.IsZero() || now.Sub(u.failedAt) >= f.interval {
+			healthy = append(healthy, u.url)
+		} else {
+			unhealthy = append(unhealthy, u)
+		}
+	}
+	sort.SliceStable(unhealthy, func(i, j int) bool {
+		return unhealthy[i].failedAt.Before(unhealthy[j].failedAt)
+	})
+	for _, u := range unhealthy {
+		healthy = append(healthy, u.url)
+	}
+	return healthy
+}
+
+func (f *failover) report(baseURL string, failed bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, u := range f.urls {
+		if u.url == baseURL {
+			if failed {
+				u.failedAt = time.Now()
+			} else {
+				u.failedAt = time.Time{}
+			}
+			return
+		}
+	}
+}
+
+// prefer sets the order of preference of the base URLs.
+func (f *failover) prefer(baseURLs []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	byURL := make(map[string]*baseURLState, len(f.urls))
+	for _, u := range f.urls {
+		byURL[u.url] = u
+	}
+	f.urls = f.urls[:0]
+	for _, u := range baseURLs {
+		f.urls = append(f.urls, byURL[u])
+	}
+}
+
+// canFailOver reports whether the request can be sent again to another base URL, following
+// the RetryPolicy for the requests with an idempotency key.
+func (c *Client) canFailOver(r *http.Request) bool {
+	return isIdempotent(r, c.retryPolicy != nil && c.retryPolicy.RetryIdempotencyKeyed)
+}
+
+// rebase returns the request sent to baseURL instead of the URL it was built with, replacing
+// the scheme, host and path prefix of its URL.
+func (c *Client) rebase(r *http.Request, baseURL string) *http.Request {
+	if baseURL == c.failover.base {
+		return r
+	}
+	from, to := c.failover.parsed[c.failover.base], c.failover.parsed[baseURL]
+
+	u := *r.URL
+	u.Scheme, u.Host, u.User = to.Scheme, to.Host, to.User
+	u.Path = to.Path + strings.TrimPrefix(r.URL.Path, from.Path)
+	if r.URL.RawPath != "" {
+		u.RawPath = to.EscapedPath() + strings.TrimPrefix(r.URL.RawPath, from.EscapedPath())
+	}
+
+	out := r.Clone(r.Context())
+	out.URL = &u
+	out.Host = u.Host
+	return out
+}
+
+// roundTripFailover sends the request to the first healthy base URL, and to the next ones while
+// it fails if it is idempotent. Without several base URLs, it is sent to the client BaseURL.
+func (c *Client) roundTripFailover(r *http.Request) (*http.Response, error) {
+	if c.failover == nil {
+		start := time.Now()
+		resp, err := c.roundTrip(r)
+		c.logAttempt(r, start, resp, err)
+		return resp, err
+	}
+
+	candidates := c.failover.candidates()
+	for i, baseURL := range candidates {
+		if i > 0 && r.GetBody != nil {
+			body, err := r.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r.Body = body
+		}
+		req := c.rebase(r, baseURL)
+		start := time.Now()
+		resp, err := c.roundTrip(req)
+		c.logAttempt(req, start, resp, err)
+
+		failed := requestOutcome(r.Context(), resp, err) == circuitFailure
+		c.failover.report(baseURL, failed)
+		if !failed || i == len(candidates)-1 || !c.canFailOver(r) {
+			return resp, err
+		}
+
+		c.logf("chat-client: %s %s failed on %s, failing over to %s", r.Method, r.URL.Path, baseURL, candidates[i+1])
+		if resp != nil {
+			// drain the body so that the connection can be reused
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+	}
+	return nil, errors.New("chat-client: no base URL")
+}
+
+// ActiveBaseURL returns the base URL the next request will be sent to.
+func (c *Client) ActiveBaseURL() string {
+	if c.failover == nil {
+		return c.BaseURL
+	}
+	return c.failover.candidates()[0]
+}
+
+// SelectBaseURLByLatency measures the latency of the base URLs set with WithBaseURLs and makes
+// them preferred in order of latency. Unreachable base URLs come last. It returns the fastest one.
+func (c *Client) SelectBaseURLByLatency(ctx context.Context) (string, error) {
+	if c.failover == nil {
+		return c.BaseURL, nil
+	}
+
+	c.failover.mu.Lock()
+	baseURLs := make([]string, len(c.failover.urls))
+	for i, u := range c.failover.urls {
+		baseURLs[i] = u.url
+	}
+	c.failover.mu.Unlock()
+
+	latencies := make([]time.Duration, len(baseURLs))
+	var wg sync.WaitGroup
+	for i, baseURL := range baseURLs {
+		wg.Add(1)
+		go func(i int, baseURL string) {
+			defer wg.Done()
+			latencies[i] = c.probeLatency(ctx, baseURL)
+		}(i, baseURL)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	order := make([]int, len(baseURLs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		li, lj := latencies[order[i]], latencies[order[j]]
+		return li >= 0 && (lj < 0 || li < lj)
+	})
+	if latencies[order[0]] < 0 {
+		return "", errors.New("chat-client: no base URL is reachable")
+	}
+
+	sorted := make([]string, len(order))
+	for i, idx := range order {
+		sorted[i] = baseURLs[idx]
+	}
+	c.failover.prefer(sorted)
+	return sorted[0], nil
+}
+
+// probeLatency returns the duration of a request to the base URL, -1 if it failed.
+func (c *Client) probeLatency(ctx context.Context, baseURL string) time.Duration {
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/", nil)
+	if err != nil {
+		return -1
+	}
+	r.Header.Set("User-Agent", c.userAgent)
+
+	start := time.Now()
+	resp, err := c.HTTP.Do(r)
+	if err != nil {
+		return -1
+	}
+	latency := time.Since(start)
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return -1
+	}
+	return latency
+}
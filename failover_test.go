@@ -0,0 +1,136 @@
+package stream_chat // nolint: golint
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type regionServer struct {
+	*httptest.Server
+	failing int32
+	calls   int32
+	delay   time.Duration
+}
+
+func newRegionServer(t *testing.T) *regionServer {
+	s := &regionServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&s.calls, 1)
+		time.Sleep(s.delay)
+		if atomic.LoadInt32(&s.failing) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"message":{"id":"msg"}}`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// failbackWait is longer than the failback interval of the tests, with a margin for slow runs.
+const failbackWait = 150 * time.Millisecond
+
+func TestClient_Failover(t *testing.T) {
+	ctx := context.Background()
+	preferred, fallback := newRegionServer(t), newRegionServer(t)
+
+	c, err := NewClient("key", "secret", WithBaseURLs(preferred.URL, fallback.URL),
+		WithFailbackInterval(50*time.Millisecond))
+	require.NoError(t, err)
+	require.Equal(t, preferred.URL, c.BaseURL)
+	require.Equal(t, preferred.URL, c.ActiveBaseURL())
+
+	atomic.StoreInt32(&preferred.failing, 1)
+	_, err = c.GetMessage(ctx, "msg")
+	require.NoError(t, err, "idempotent requests fail over right away")
+	require.EqualValues(t, 1, atomic.LoadInt32(&preferred.calls))
+	require.EqualValues(t, 1, atomic.LoadInt32(&fallback.calls))
+	require.Equal(t, fallback.URL, c.ActiveBaseURL())
+
+	ch := c.Channel("messaging", "general")
+	_, err = ch.SendMessage(ctx, &Message{Text: "hello"}, "tommaso")
+	require.NoError(t, err, "the unhealthy base URL is skipped")
+	require.EqualValues(t, 1, atomic.LoadInt32(&preferred.calls))
+
+	time.Sleep(failbackWait)
+	require.Equal(t, preferred.URL, c.ActiveBaseURL(), "the preferred base URL is tried again after the interval")
+	_, err = ch.SendMessage(ctx, &Message{Text: "hello"}, "tommaso")
+	require.Error(t, err, "requests without idempotency key do not fail over")
+	require.EqualValues(t, 2, atomic.LoadInt32(&preferred.calls))
+
+	atomic.StoreInt32(&preferred.failing, 0)
+	time.Sleep(failbackWait)
+	_, err = c.GetMessage(ctx, "msg")
+	require.NoError(t, err)
+	require.EqualValues(t, 3, atomic.LoadInt32(&preferred.calls))
+	require.Equal(t, preferred.URL, c.ActiveBaseURL())
+}
+
+func TestClient_FailoverRebase(t *testing.T) {
+	ctx := context.Background()
+	preferred := newRegionServer(t)
+	atomic.StoreInt32(&preferred.failing, 1)
+
+	paths := make(chan string, 1)
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.EscapedPath()
+		_, _ = w.Write([]byte(`{"message":{"id":"a/b"}}`))
+	}))
+	t.Cleanup(fallback.Close)
+
+	// the preferred base URL is not in canonical form, the fallback one has a path
+	c, err := NewClient("key", "secret",
+		WithBaseURLs(strings.Replace(preferred.URL, "http://", "HTTP://", 1)+"/", fallback.URL+"/edge/"))
+	require.NoError(t, err)
+
+	_, err = c.GetMessage(ctx, "a/b")
+	require.NoError(t, err)
+	require.EqualValues(t, 1, atomic.LoadInt32(&preferred.calls))
+	require.Equal(t, "/edge/messages/a%2Fb", <-paths)
+
+	_, err = NewClient("key", "secret", WithBaseURLs(preferred.URL, "/no/host"))
+	require.EqualError(t, err, "invalid base URL /no/host: scheme or host missing")
+}
+
+func TestClient_FailoverKeyed(t *testing.T) {
+	ctx := WithIdempotencyKey(context.Background(), "key")
+
+	for _, keyed := range []bool{false, true} {
+		preferred, fallback := newRegionServer(t), newRegionServer(t)
+		policy := DefaultRetryPolicy()
+		policy.MaxAttempts = 1
+		policy.RetryIdempotencyKeyed = keyed
+		c, err := NewClient("key", "secret", WithBaseURLs(preferred.URL, fallback.URL), WithRetryPolicy(policy))
+		require.NoError(t, err)
+		c.BaseURL = "http://127.0.0.1:1"
+
+		atomic.StoreInt32(&preferred.failing, 1)
+		_, err = c.Channel("messaging", "general").SendMessage(ctx, &Message{Text: "hello"}, "tommaso")
+		require.Equal(t, keyed, err == nil, "keyed requests fail over if the retry policy allows it")
+		require.EqualValues(t, 1, atomic.LoadInt32(&preferred.calls))
+		if keyed {
+			require.EqualValues(t, 1, atomic.LoadInt32(&fallback.calls), "the request is rebased from the preferred URL")
+		}
+	}
+}
+
+func TestClient_SelectBaseURLByLatency(t *testing.T) {
+	slow, fast, down := newRegionServer(t), newRegionServer(t), newRegionServer(t)
+	slow.delay = 20 * time.Millisecond
+	atomic.StoreInt32(&down.failing, 1)
+
+	c, err := NewClient("key", "secret", WithBaseURLs(down.URL, slow.URL, fast.URL))
+	require.NoError(t, err)
+
+	selected, err := c.SelectBaseURLByLatency(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, fast.URL, selected)
+	require.Equal(t, []string{fast.URL, slow.URL, down.URL}, c.failover.candidates())
+}
@@ -1,9 +1,11 @@
-module github.com/GetStream/stream-chat-go/v3
+module github.com/GetStream/stream-chat-go/v4
 
 go 1.14
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/golang-jwt/jwt/v4 v4.0.0
+	github.com/kr/pretty v0.1.0 // indirect
 	github.com/stretchr/testify v1.7.0
+	gopkg.in/check.v1 v1.0.0-20190902080502-41f04d3bba15 // indirect
 )
@@ -0,0 +1,191 @@
+package stream_chat // nolint: golint
+
+import (
+	"context"
+	"time"
+)
+
+// UserAPI is the part of ChatClient managing users, their devices and their tokens.
+type UserAPI interface {
+	UpsertUser(ctx context.Context, user *User) (*User, error)
+	UpdateUser(ctx context.Context, user *User) (*User, error)
+	UpsertUsers(ctx context.Context, users ...*User) (map[string]*User, error)
+	UpdateUsers(ctx context.Context, users ...*User) (map[string]*User, error)
+	PartialUpdateUser(ctx context.Context, update PartialUserUpdate) (*User, error)
+	PartialUpdateUsers(ctx context.Context, updates []PartialUserUpdate) (map[string]*User, error)
+	BulkUpsertUsers(ctx context.Context, users []*User, options ...BulkOption) (map[string]*User, error)
+	BulkPartialUpdateUsers(ctx context.Context, updates []PartialUserUpdate, options ...BulkOption) (map[string]*User, error)
+	QueryUsers(ctx context.Context, q *QueryOption, sorters ...*SortOption) ([]*User, error)
+	ExportUser(ctx context.Context, targetID string, options map[string][]string) (*User, error)
+	DeactivateUser(ctx context.Context, targetID string, options map[string]interface{}) error
+	ReactivateUser(ctx context.Context, targetID string, options map[string]interface{}) error
+	DeleteUser(ctx context.Context, targetID string, options map[string][]string) error
+	DeleteUsers(ctx context.Context, userIDs []string, options DeleteUserOptions) (string, error)
+	BulkDeleteUsers(ctx context.Context, userIDs []string, deleteOptions DeleteUserOptions, options ...BulkOption) ([]string, error)
+	SendUserCustomEvent(ctx context.Context, targetUserID string, event *UserCustomEvent) error
+
+	GetDevices(ctx context.Context, userID string) ([]*Device, error)
+	AddDevice(ctx context.Context, device *Device) error
+	DeleteDevice(ctx context.Context, userID, deviceID string) error
+
+	CreateToken(userID string, expire time.Time, issuedAt ...time.Time) (string, error)
+	CreateTokenWithClaims(userID string, claims map[string]interface{}) (string, error)
+	ParseUserToken(token string) (map[string]interface{}, error)
+	VerifyUserToken(token string) (string, error)
+	RevokeUserToken(ctx context.Context, userID string, before *time.Time) error
+	RevokeUsersTokens(ctx context.Context, userIDs []string, before *time.Time) error
+}
+
+// ModerationAPI is the part of ChatClient muting, flagging and banning users and messages.
+type ModerationAPI interface {
+	MuteUser(ctx context.Context, targetID, userID string, options map[string]interface{}) error
+	MuteUsers(ctx context.Context, targetIDs []string, userID string, options map[string]interface{}) error
+	UnmuteUser(ctx context.Context, targetID, userID string) error
+	UnmuteUsers(ctx context.Context, targetIDs []string, userID string) error
+	FlagUser(ctx context.Context, targetID string, options map[string]interface{}) error
+	UnFlagUser(ctx context.Context, targetID string, options map[string]interface{}) error
+	FlagMessage(ctx context.Context, msgID, userID string) error
+	UnflagMessage(ctx context.Context, msgID, userID string) error
+	QueryMessageFlags(ctx context.Context, q *QueryOption) ([]*MessageFlag, error)
+	BanUser(ctx context.Context, targetID, userID string, options map[string]interface{}) error
+	UnBanUser(ctx context.Context, targetID string, options map[string]string) error
+	ShadowBan(ctx context.Context, userID, bannedByID string, options map[string]interface{}) error
+	RemoveShadowBan(ctx context.Context, userID string, options map[string]string) error
+}
+
+// MessageAPI is the part of ChatClient managing messages outside of a channel.
+type MessageAPI interface {
+	GetMessage(ctx context.Context, msgID string) (*Message, error)
+	UpdateMessage(ctx context.Context, msg *Message, msgID string) (*Message, error)
+	PartialUpdateMessage(ctx context.Context, messageID string, updates PartialUpdate, options map[string]interface{}) (*Message, error)
+	PinMessage(ctx context.Context, msgID, pinnedByID string, expiration *time.Time) (*Message, error)
+	UnPinMessage(ctx context.Context, msgID, userID string) (*Message, error)
+	DeleteMessage(ctx context.Context, msgID string) error
+	HardDeleteMessage(ctx context.Context, msgID string) error
+	MarkAllRead(ctx context.Context, userID string) error
+	Search(ctx context.Context, request SearchRequest) ([]*Message, error)
+	SearchWithFullResponse(ctx context.Context, request SearchRequest) (*SearchResponse, error)
+}
+
+// ChannelAPI is implemented by *Channel, to substitute it in tests.
+type ChannelAPI interface {
+	// Data returns the fields of the channel, like its ID and members.
+	Data() *Channel
+
+	Query(ctx context.Context, data map[string]interface{}) error
+	Update(ctx context.Context, options map[string]interface{}, message *Message) error
+	PartialUpdate(ctx context.Context, update PartialUpdate) error
+	Delete(ctx context.Context) error
+	Truncate(ctx context.Context) error
+	Show(ctx context.Context, userID string) error
+	Hide(ctx context.Context, userID string) error
+	HideWithHistoryClear(ctx context.Context, userID string) error
+	Mute(ctx context.Context, userID string, expiration *time.Duration) (*ChannelMuteResponse, error)
+	Unmute(ctx context.Context, userID string) error
+	MarkRead(ctx context.Context, userID string, options map[string]interface{}) error
+
+	AddMembers(ctx context.Context, userIDs []string, message *Message, options map[string]interface{}) error
+	RemoveMembers(ctx context.Context, userIDs []string, message *Message) error
+	QueryMembers(ctx context.Context, q *QueryOption, sorters ...*SortOption) ([]*ChannelMember, error)
+	AddModerators(ctx context.Context, userIDs ...string) error
+	AddModeratorsWithMessage(ctx context.Context, userIDs []string, msg *Message) error
+	DemoteModerators(ctx context.Context, userIDs ...string) error
+	DemoteModeratorsWithMessage(ctx context.Context, userIDs []string, msg *Message) error
+	InviteMembers(ctx context.Context, userIDs ...string) error
+	InviteMembersWithMessage(ctx context.Context, userIDs []string, msg *Message) error
+	AcceptInvite(ctx context.Context, userID string, message *Message) error
+	RejectInvite(ctx context.Context, userID string, message *Message) error
+	BanUser(ctx context.Context, targetID, userID string, options map[string]interface{}) error
+	UnBanUser(ctx context.Context, targetID string, options map[string]string) error
+	ShadowBan(ctx context.Context, userID, bannedByID string, options map[string]interface{}) error
+	RemoveShadowBan(ctx context.Context, userID string) error
+
+	SendMessage(ctx context.Context, message *Message, userID string, options ...SendMessageOption) (*Message, error)
+	ImportMessages(ctx context.Context, messages ...*Message) (*ImportChannelMessagesResponse, error)
+	GetReplies(ctx context.Context, parentID string, options map[string][]string) ([]*Message, error)
+	SendAction(ctx context.Context, msgID string, formData map[string]string) (*Message, error)
+	SendReaction(ctx context.Context, reaction *Reaction, messageID, userID string) (*Message, error)
+	DeleteReaction(ctx context.Context, messageID, reactionType, userID string) (*Message, error)
+	GetReactions(ctx context.Context, messageID string, options map[string][]string) ([]*Reaction, error)
+	SendEvent(ctx context.Context, event *Event, userID string) error
+
+	SendFile(ctx context.Context, request SendFileRequest) (string, error)
+	SendImage(ctx context.Context, request SendFileRequest) (string, error)
+	DeleteFile(ctx context.Context, location string) error
+	DeleteImage(ctx context.Context, location string) error
+}
+
+// ChatClient is implemented by *Client, to substitute it in tests. The streammock package
+// provides an implementation recording the calls and returning programmed results.
+type ChatClient interface {
+	UserAPI
+	ModerationAPI
+	MessageAPI
+
+	// ChannelAPI returns the channel with the given type and ID.
+	ChannelAPI(channelType, channelID string) ChannelAPI
+	CreateChannelAPI(ctx context.Context, chanType, chanID, userID string, data map[string]interface{}) (ChannelAPI, error)
+	QueryChannelsAPI(ctx context.Context, q *QueryOption, sort ...*SortOption) ([]ChannelAPI, error)
+	DeleteChannels(ctx context.Context, cids []string, hardDelete bool) (string, error)
+	BulkDeleteChannels(ctx context.Context, cids []string, hardDelete bool, options ...BulkOption) ([]string, error)
+	ExportChannels(ctx context.Context, channels []*ExportableChannel, clearDeletedMessageText, includeTruncatedMessages *bool) (string, error)
+	GetExportChannelsTask(ctx context.Context, taskID string) (*Task, error)
+	GetTask(ctx context.Context, id string) (*Task, error)
+	WaitForTask(ctx context.Context, taskID string, options ...WaitOption) (*Task, error)
+
+	CreateChannelType(ctx context.Context, chType *ChannelType) (*ChannelType, error)
+	GetChannelType(ctx context.Context, chanType string) (*ChannelType, error)
+	ListChannelTypes(ctx context.Context) (map[string]*ChannelType, error)
+	UpdateChannelType(ctx context.Context, name string, options map[string]interface{}) error
+	DeleteChannelType(ctx context.Context, name string) error
+
+	CreateCommand(ctx context.Context, cmd *Command) (*Command, error)
+	GetCommand(ctx context.Context, cmdName string) (*Command, error)
+	ListCommands(ctx context.Context) ([]*Command, error)
+	UpdateCommand(ctx context.Context, cmdName string, options map[string]interface{}) (*Command, error)
+	DeleteCommand(ctx context.Context, cmdName string) error
+
+	GetAppConfig(ctx context.Context) (*AppConfig, error)
+	UpdateAppSettings(ctx context.Context, settings *AppSettings) error
+	RevokeTokens(ctx context.Context, before *time.Time) error
+	GetRateLimits(ctx context.Context, options ...GetRateLimitsOption) (GetRateLimitsResponse, error)
+	VerifyWebhook(body, signature []byte) bool
+}
+
+var (
+	_ ChatClient = (*Client)(nil)
+	_ ChannelAPI = (*Channel)(nil)
+)
+
+// ChannelAPI returns the channel with the given type and ID, like Channel, as a ChannelAPI.
+func (c *Client) ChannelAPI(channelType, channelID string) ChannelAPI {
+	return c.Channel(channelType, channelID)
+}
+
+// CreateChannelAPI is like CreateChannel, returning the channel as a ChannelAPI.
+func (c *Client) CreateChannelAPI(ctx context.Context, chanType, chanID, userID string, data map[string]interface{}) (ChannelAPI, error) {
+	ch, err := c.CreateChannel(ctx, chanType, chanID, userID, data)
+	if err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+// QueryChannelsAPI is like QueryChannels, returning the channels as ChannelAPI.
+func (c *Client) QueryChannelsAPI(ctx context.Context, q *QueryOption, sort ...*SortOption) ([]ChannelAPI, error) {
+	channels, err := c.QueryChannels(ctx, q, sort...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ChannelAPI, len(channels))
+	for i, ch := range channels {
+		result[i] = ch
+	}
+	return result, nil
+}
+
+// Data returns the channel itself, it implements ChannelAPI.
+func (ch *Channel) Data() *Channel {
+	return ch
+}
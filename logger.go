@@ -0,0 +1,225 @@
+package stream_chat // nolint: golint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Logger is used by the client to report events which do not surface as errors,
+// like retried requests. *log.Logger satisfies this interface.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// LogLevel is the severity of a log entry.
+type LogLevel int
+
+const (
+	// LogLevelDebug entries include the bodies of the requests and responses, with secrets redacted.
+	LogLevelDebug LogLevel = iota
+	// LogLevelInfo entries report the successful API requests.
+	LogLevelInfo
+	// LogLevelWarn entries report the requests failed with a client error and the retries.
+	LogLevelWarn
+	// LogLevelError entries report the requests failed with a server error or without response.
+	LogLevelError
+)
+
+// String implements fmt.Stringer.
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelDebug:
+		return "debug"
+	case LogLevelInfo:
+		return "info"
+	case LogLevelWarn:
+		return "warn"
+	case LogLevelError:
+		return "error"
+	default:
+		return fmt.Sprintf("LogLevel(%d)", int(l))
+	}
+}
+
+// LogField is a key/value pair attached to a log entry.
+type LogField struct {
+	Key   string
+	Value interface{}
+}
+
+// LeveledLogger receives structured log entries from the client, set with WithLeveledLogger.
+//
+// Every attempt of an API request is logged with the fields method, path, status, latency and
+// request_id, or error when no response was received. At debug level, the entries also have the
+// query, request_headers, request_body and response_body fields, with the api_key parameter, the
+// Authorization header, push credentials and tokens redacted.
+type LeveledLogger interface {
+	Log(level LogLevel, msg string, fields ...LogField)
+}
+
+// WithLeveledLogger sets the logger receiving structured log entries. It replaces the logger
+// set with WithLogger.
+func WithLeveledLogger(logger LeveledLogger) ClientOption {
+	return func(o *clientOptions) {
+		o.leveledLogger = logger
+	}
+}
+
+// WithLogLevel sets the minimum level of the logged entries, LogLevelWarn by default.
+func WithLogLevel(level LogLevel) ClientOption {
+	return func(o *clientOptions) {
+		o.logLevel = level
+	}
+}
+
+func (c *Client) logEnabled(level LogLevel) bool {
+	return level >= c.logLevel && (c.leveledLogger != nil || c.logger != nil)
+}
+
+func (c *Client) log(level LogLevel, msg string, fields ...LogField) {
+	if !c.logEnabled(level) {
+		return
+	}
+
+	if c.leveledLogger != nil {
+		c.leveledLogger.Log(level, msg, fields...)
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString(msg)
+	for _, f := range fields {
+		fmt.Fprintf(&b, " %s=%v", f.Key, f.Value)
+	}
+	c.logger.Printf("%s", b.String())
+}
+
+func (c *Client) logf(format string, v ...interface{}) {
+	c.log(LogLevelWarn, fmt.Sprintf(format, v...))
+}
+
+// logAttempt logs an attempt of an API request started at start.
+// At debug level, the response body is read and replaced so that it can be logged.
+func (c *Client) logAttempt(r *http.Request, start time.Time, resp *http.Response, err error) {
+	level := LogLevelInfo
+	switch {
+	case err != nil || resp.StatusCode >= 500:
+		level = LogLevelError
+	case resp.StatusCode >= 400:
+		level = LogLevelWarn
+	}
+	if !c.logEnabled(level) {
+		return
+	}
+
+	fields := []LogField{
+		{"method", r.Method},
+		{"path", r.URL.Path},
+		{"latency", time.Since(start)},
+	}
+	if err != nil {
+		fields = append(fields, LogField{"error", err})
+	} else {
+		fields = append(fields, LogField{"status", resp.StatusCode}, LogField{"request_id", resp.Header.Get(requestIDHeader)})
+	}
+
+	if c.logEnabled(LogLevelDebug) {
+		query := r.URL.Query()
+		if query.Get("api_key") != "" {
+			query.Set("api_key", redactedValue)
+		}
+		fields = append(fields, LogField{"query", query.Encode()}, LogField{"request_headers", redactHeaders(r.Header)})
+
+		if r.GetBody != nil {
+			if body, err := r.GetBody(); err == nil {
+				b, _ := ioutil.ReadAll(body)
+				fields = append(fields, LogField{"request_body", redactJSON(gunzipForLog(r.Header, b))})
+			}
+		}
+		if resp != nil && resp.Body != nil {
+			b, err := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = ioutil.NopCloser(io.MultiReader(bytes.NewReader(b), errReader{err}))
+			fields = append(fields, LogField{"response_body", redactJSON(gunzipForLog(resp.Header, b))})
+		}
+	}
+
+	msg := "chat-client: API request"
+	if level > LogLevelInfo {
+		msg = "chat-client: API request failed"
+	}
+	c.log(level, msg, fields...)
+}
+
+// errReader returns err, io.EOF if nil.
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	if r.err == nil {
+		return 0, io.EOF
+	}
+	return 0, r.err
+}
+
+const redactedValue = "[REDACTED]"
+
+func redactHeaders(h http.Header) http.Header {
+	redacted := h.Clone()
+	if redacted.Get("Authorization") != "" {
+		redacted.Set("Authorization", redactedValue)
+	}
+	return redacted
+}
+
+// redactJSON returns the JSON body with the values of sensitive fields redacted.
+// Bodies which are not JSON are returned as they are.
+func redactJSON(body []byte) string {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return string(body)
+	}
+
+	redactFields(v)
+	b, err := json.Marshal(v)
+	if err != nil {
+		return string(body)
+	}
+	return string(b)
+}
+
+func redactFields(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, field := range v {
+			if field != nil && sensitiveField(k) {
+				v[k] = redactedValue
+				continue
+			}
+			redactFields(field)
+		}
+	case []interface{}:
+		for _, item := range v {
+			redactFields(item)
+		}
+	}
+}
+
+// sensitiveField reports whether a JSON field holds credentials: API keys and secrets, push
+// credentials like APNConfig.AuthKey and FirebaseConfig.ServerKey, and tokens.
+func sensitiveField(key string) bool {
+	switch strings.ToLower(key) {
+	case "api_key", "api_secret", "secret", "auth_key", "server_key", "p12_cert", "token":
+		return true
+	}
+	return strings.HasSuffix(strings.ToLower(key), "_token")
+}
@@ -0,0 +1,105 @@
+package stream_chat // nolint: golint
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type logEntry struct {
+	level  LogLevel
+	msg    string
+	fields map[string]interface{}
+}
+
+type recordingLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (l *recordingLogger) Log(level LogLevel, msg string, fields ...LogField) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e := logEntry{level: level, msg: msg, fields: make(map[string]interface{})}
+	for _, f := range fields {
+		e.fields[f.Key] = f.Value
+	}
+	l.entries = append(l.entries, e)
+}
+
+func newLoggerTestServer(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(requestIDHeader, "request-id")
+		if r.Method == http.MethodGet {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":16,"message":"does not exist"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"duration":"1ms","push_token":"device-token"}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestClient_LeveledLogger(t *testing.T) {
+	ctx := context.Background()
+	srv := newLoggerTestServer(t)
+
+	t.Run("default level", func(t *testing.T) {
+		logger := &recordingLogger{}
+		c, err := NewClient("key", "secret", WithBaseURL(srv.URL), WithLeveledLogger(logger))
+		require.NoError(t, err)
+
+		require.NoError(t, c.UpdateAppSettings(ctx, NewAppSettings()))
+		_, err = c.GetMessage(ctx, "missing")
+		require.True(t, IsNotFound(err))
+
+		require.Len(t, logger.entries, 1, "only failed requests are logged")
+		e := logger.entries[0]
+		require.Equal(t, LogLevelWarn, e.level)
+		require.Equal(t, http.MethodGet, e.fields["method"])
+		require.Equal(t, "/messages/missing", e.fields["path"])
+		require.Equal(t, http.StatusNotFound, e.fields["status"])
+		require.Equal(t, "request-id", e.fields["request_id"])
+		require.NotZero(t, e.fields["latency"])
+		require.NotContains(t, e.fields, "response_body")
+	})
+
+	t.Run("debug level redacts secrets", func(t *testing.T) {
+		logger := &recordingLogger{}
+		c, err := NewClient("key", "secret", WithBaseURL(srv.URL), WithLeveledLogger(logger), WithLogLevel(LogLevelDebug))
+		require.NoError(t, err)
+
+		settings := NewAppSettings().
+			SetAPNConfig(APNConfig{AuthKey: []byte("apn-auth-key"), KeyID: "key-id"}).
+			SetFirebaseConfig(FirebaseConfig{ServerKey: "firebase-server-key"})
+		require.NoError(t, c.UpdateAppSettings(ctx, settings))
+
+		require.Len(t, logger.entries, 1)
+		e := logger.entries[0]
+		require.Equal(t, LogLevelInfo, e.level)
+		require.Equal(t, "api_key=%5BREDACTED%5D", e.fields["query"])
+		require.Equal(t, redactedValue, e.fields["request_headers"].(http.Header).Get("Authorization"))
+		require.Equal(t, `{"apn_config":{"auth_key":"[REDACTED]","development":false,"enabled":false,"key_id":"key-id","notification_template":""},`+
+			`"firebase_config":{"enabled":false,"server_key":"[REDACTED]"}}`, e.fields["request_body"])
+		require.Equal(t, `{"duration":"1ms","push_token":"[REDACTED]"}`, e.fields["response_body"])
+	})
+
+	t.Run("printf logger", func(t *testing.T) {
+		var buf bytes.Buffer
+		c, err := NewClient("key", "secret", WithBaseURL(srv.URL), WithLogger(log.New(&buf, "", 0)))
+		require.NoError(t, err)
+
+		_, err = c.GetMessage(ctx, "missing")
+		require.Error(t, err)
+		require.Regexp(t, `^chat-client: API request failed method=GET path=/messages/missing latency=\S+ status=404 request_id=request-id\n$`,
+			buf.String())
+	})
+}
@@ -1,11 +1,17 @@
 package stream_chat // nolint: golint
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha1" //nolint: gosec
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"path"
+	"strings"
 	"time"
 )
 
@@ -20,7 +26,8 @@ const (
 )
 
 type Message struct {
-	ID string `json:"id"`
+	ID  string `json:"id"`
+	CID string `json:"cid,omitempty"` // CID of the channel of the message
 
 	Text string `json:"text"`
 	HTML string `json:"html"`
@@ -78,6 +85,7 @@ func (m *Message) toRequest() messageRequest {
 	var req messageRequest
 
 	req.Message = messageRequestMessage{
+		ID:            m.ID,
 		Text:          m.Text,
 		Attachments:   m.Attachments,
 		User:          messageRequestUser{ID: m.User.ID},
@@ -103,6 +111,7 @@ type messageRequest struct {
 }
 
 type messageRequestMessage struct {
+	ID             string             `json:"id,omitempty"`
 	Text           string             `json:"text"`
 	Attachments    []*Attachment      `json:"attachments"`
 	User           messageRequestUser `json:"user"`
@@ -193,8 +202,73 @@ func MessageSkipPush(r *messageRequest) {
 	}
 }
 
+// MessageAutoID is an option that can be given to SendMessage to generate a random ID for a
+// message without one, so that retrying the request cannot create the message twice.
+func MessageAutoID(r *messageRequest) {
+	if r != nil && r.Message.ID == "" {
+		r.Message.ID = randomUUID()
+	}
+}
+
+// MessageIDFromKey returns an option setting the ID of the message to a UUID derived from key,
+// so that sending the message again with the same key, even from another process, does not
+// create a duplicate. Message IDs are unique within the app, so must be the keys.
+func MessageIDFromKey(key string) SendMessageOption {
+	return func(r *messageRequest) {
+		if r != nil {
+			r.Message.ID = keyUUID(key)
+		}
+	}
+}
+
+// messageIDNamespace is the namespace of the UUIDs generated by MessageIDFromKey.
+const messageIDNamespace = "\x5b\x1c\x4e\x2d\x8f\x03\x4a\x7e\x9b\x61\xd2\x35\x0c\xe8\x47\xa9"
+
+// randomUUID returns a version 4 UUID.
+func randomUUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return formatUUID(b)
+}
+
+// keyUUID returns the version 5 UUID of key.
+func keyUUID(key string) string {
+	sum := sha1.Sum([]byte(messageIDNamespace + key)) //nolint: gosec
+	b := sum[:16]
+	b[6] = b[6]&0x0f | 0x50
+	b[8] = b[8]&0x3f | 0x80
+	return formatUUID(b)
+}
+
+func formatUUID(b []byte) string {
+	h := hex.EncodeToString(b)
+	return strings.Join([]string{h[:8], h[8:12], h[12:16], h[16:20], h[20:]}, "-")
+}
+
+// sentMessage returns the message with the given ID if the user already sent it to the channel.
+// The API rejects a message with the ID of an existing one with an input error, without a
+// dedicated code: the message is fetched to tell it apart from other input errors, and from a
+// message with the same ID in another channel or by another user.
+func (ch *Channel) sentMessage(ctx context.Context, msgID, userID string) (*Message, bool) {
+	msg, err := ch.client.GetMessage(ctx, msgID)
+	if err != nil || msg == nil {
+		return nil, false
+	}
+	if msg.CID != ch.Type+":"+ch.ID || msg.User == nil || msg.User.ID != userID {
+		return nil, false
+	}
+	return msg, true
+}
+
 // SendMessage sends a message to the channel. Returns full message details from server.
-func (ch *Channel) SendMessage(message *Message, userID string, options ...SendMessageOption) (*Message, error) {
+//
+// If the message has an ID, set by the caller or with the MessageAutoID or MessageIDFromKey
+// options, sending it is idempotent: the request carries the ID as idempotency key so that the
+// RetryPolicy can retry it, and when a message with this ID already exists, it is returned
+// instead of an error if it was sent to this channel by the same user.
+func (ch *Channel) SendMessage(ctx context.Context, message *Message, userID string, options ...SendMessageOption) (*Message, error) {
 	switch {
 	case message == nil:
 		return nil, errors.New("message is nil")
@@ -209,9 +283,17 @@ func (ch *Channel) SendMessage(message *Message, userID string, options ...SendM
 	for _, op := range options {
 		op(&req)
 	}
+	if req.Message.ID != "" && idempotencyKeyFromContext(ctx) == "" {
+		ctx = WithIdempotencyKey(ctx, "message-"+req.Message.ID)
+	}
 
 	var resp messageResponse
-	err := ch.client.makeRequest(http.MethodPost, p, nil, req, &resp)
+	err := ch.client.makeRequest(ctx, http.MethodPost, p, nil, req, &resp)
+	if req.Message.ID != "" && IsInputError(err) {
+		if msg, ok := ch.sentMessage(ctx, req.Message.ID, userID); ok {
+			return msg, nil
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -220,7 +302,7 @@ func (ch *Channel) SendMessage(message *Message, userID string, options ...SendM
 }
 
 // MarkAllRead marks all messages as read for userID.
-func (c *Client) MarkAllRead(userID string) error {
+func (c *Client) MarkAllRead(ctx context.Context, userID string) error {
 	if userID == "" {
 		return errors.New("user ID must be not empty")
 	}
@@ -231,11 +313,11 @@ func (c *Client) MarkAllRead(userID string) error {
 		},
 	}
 
-	return c.makeRequest(http.MethodPost, "channels/read", nil, data, nil)
+	return c.makeRequest(ctx, http.MethodPost, "channels/read", nil, data, nil)
 }
 
 // GetMessage returns message by ID.
-func (c *Client) GetMessage(msgID string) (*Message, error) {
+func (c *Client) GetMessage(ctx context.Context, msgID string) (*Message, error) {
 	if msgID == "" {
 		return nil, errors.New("message ID must be not empty")
 	}
@@ -244,7 +326,7 @@ func (c *Client) GetMessage(msgID string) (*Message, error) {
 
 	p := path.Join("messages", url.PathEscape(msgID))
 
-	err := c.makeRequest(http.MethodGet, p, nil, nil, &resp)
+	err := c.makeRequest(ctx, http.MethodGet, p, nil, nil, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -253,19 +335,25 @@ func (c *Client) GetMessage(msgID string) (*Message, error) {
 }
 
 // UpdateMessage updates message with given msgID.
-func (c *Client) UpdateMessage(msg *Message, msgID string) (*Message, error) {
+func (c *Client) UpdateMessage(ctx context.Context, msg *Message, msgID string) (*Message, error) {
 	switch {
 	case msg == nil:
 		return nil, errors.New("message is nil")
 	case msgID == "":
 		return nil, errors.New("message ID must be not empty")
+	case msg.ID != "" && msg.ID != msgID:
+		return nil, fmt.Errorf("message ID %s does not match %s", msg.ID, msgID)
 	}
 
 	var resp messageResponse
 
 	p := path.Join("messages", url.PathEscape(msgID))
 
-	err := c.makeRequest(http.MethodPost, p, nil, msg.toRequest(), &resp)
+	// the message is identified by the path, the ID is not updated
+	req := msg.toRequest()
+	req.Message.ID = ""
+
+	err := c.makeRequest(ctx, http.MethodPost, p, nil, req, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -275,7 +363,7 @@ func (c *Client) UpdateMessage(msg *Message, msgID string) (*Message, error) {
 
 // PartialUpdateMessage partially updates message with given msgID.
 // options["skip_enrich_url"] do not try to enrich the URLs within message.
-func (c *Client) PartialUpdateMessage(messageID string, updates PartialUpdate, options map[string]interface{}) (*Message, error) {
+func (c *Client) PartialUpdateMessage(ctx context.Context, messageID string, updates PartialUpdate, options map[string]interface{}) (*Message, error) {
 	switch {
 	case len(updates.Set) == 0 && len(updates.Unset) == 0:
 		return nil, errors.New("updates should not be empty")
@@ -297,7 +385,7 @@ func (c *Client) PartialUpdateMessage(messageID string, updates PartialUpdate, o
 		data[k] = v
 	}
 
-	err := c.makeRequest(http.MethodPut, p, nil, data, &resp)
+	err := c.makeRequest(ctx, http.MethodPut, p, nil, data, &resp)
 	if err != nil {
 		return nil, err
 	}
@@ -306,7 +394,7 @@ func (c *Client) PartialUpdateMessage(messageID string, updates PartialUpdate, o
 }
 
 // PinMessage pins the message with given msgID.
-func (c *Client) PinMessage(msgID, pinnedByID string, expiration *time.Time) (*Message, error) {
+func (c *Client) PinMessage(ctx context.Context, msgID, pinnedByID string, expiration *time.Time) (*Message, error) {
 	updates := PartialUpdate{
 		Set: map[string]interface{}{
 			"pinned": true,
@@ -320,11 +408,11 @@ func (c *Client) PinMessage(msgID, pinnedByID string, expiration *time.Time) (*M
 		"user_id": pinnedByID,
 	}
 
-	return c.PartialUpdateMessage(msgID, updates, options)
+	return c.PartialUpdateMessage(ctx, msgID, updates, options)
 }
 
 // UnPinMessage unpins the message with given msgID.
-func (c *Client) UnPinMessage(msgID, userID string) (*Message, error) {
+func (c *Client) UnPinMessage(ctx context.Context, msgID, userID string) (*Message, error) {
 	updates := PartialUpdate{
 		Set: map[string]interface{}{
 			"pinned": false,
@@ -335,18 +423,18 @@ func (c *Client) UnPinMessage(msgID, userID string) (*Message, error) {
 		"user_id": userID,
 	}
 
-	return c.PartialUpdateMessage(msgID, updates, options)
+	return c.PartialUpdateMessage(ctx, msgID, updates, options)
 }
 
-func (c *Client) DeleteMessage(msgID string) error {
-	return c.deleteMessage(msgID, false)
+func (c *Client) DeleteMessage(ctx context.Context, msgID string) error {
+	return c.deleteMessage(ctx, msgID, false)
 }
 
-func (c *Client) HardDeleteMessage(msgID string) error {
-	return c.deleteMessage(msgID, true)
+func (c *Client) HardDeleteMessage(ctx context.Context, msgID string) error {
+	return c.deleteMessage(ctx, msgID, true)
 }
 
-func (c *Client) deleteMessage(msgID string, hard bool) error {
+func (c *Client) deleteMessage(ctx context.Context, msgID string, hard bool) error {
 	if msgID == "" {
 		return errors.New("message ID must be not empty")
 	}
@@ -356,7 +444,7 @@ func (c *Client) deleteMessage(msgID string, hard bool) error {
 	if hard {
 		params["hard"] = []string{"true"}
 	}
-	return c.makeRequest(http.MethodDelete, p, params, nil, nil)
+	return c.makeRequest(ctx, http.MethodDelete, p, params, nil, nil)
 }
 
 type MessageFlag struct {
@@ -402,7 +490,7 @@ type MessageFlag struct {
 	RejectedAt time.Time `json:"rejected_at"`
 }
 
-func (c *Client) FlagMessage(msgID, userID string) error {
+func (c *Client) FlagMessage(ctx context.Context, msgID, userID string) error {
 	if msgID == "" {
 		return errors.New("message ID is empty")
 	}
@@ -416,10 +504,10 @@ func (c *Client) FlagMessage(msgID, userID string) error {
 		"user_id":           userID,
 	}
 
-	return c.makeRequest(http.MethodPost, "moderation/flag", nil, options, nil)
+	return c.makeRequest(ctx, http.MethodPost, "moderation/flag", nil, options, nil)
 }
 
-func (c *Client) UnflagMessage(msgID, userID string) error {
+func (c *Client) UnflagMessage(ctx context.Context, msgID, userID string) error {
 	if msgID == "" {
 		return errors.New("message ID is empty")
 	}
@@ -433,7 +521,7 @@ func (c *Client) UnflagMessage(msgID, userID string) error {
 		"user_id":           userID,
 	}
 
-	return c.makeRequest(http.MethodPost, "moderation/unflag", nil, options, nil)
+	return c.makeRequest(ctx, http.MethodPost, "moderation/unflag", nil, options, nil)
 }
 
 type repliesResponse struct {
@@ -442,7 +530,7 @@ type repliesResponse struct {
 
 // GetReplies returns list of the message replies for a parent message.
 // options: Pagination params, ie {limit:10, idlte: 10}
-func (ch *Channel) GetReplies(parentID string, options map[string][]string) ([]*Message, error) {
+func (ch *Channel) GetReplies(ctx context.Context, parentID string, options map[string][]string) ([]*Message, error) {
 	if parentID == "" {
 		return nil, errors.New("parent ID is empty")
 	}
@@ -451,7 +539,7 @@ func (ch *Channel) GetReplies(parentID string, options map[string][]string) ([]*
 
 	var resp repliesResponse
 
-	err := ch.client.makeRequest(http.MethodGet, p, options, nil, &resp)
+	err := ch.client.makeRequest(ctx, http.MethodGet, p, options, nil, &resp)
 
 	return resp.Messages, err
 }
@@ -462,7 +550,7 @@ type sendActionRequest struct {
 }
 
 // SendAction for a message.
-func (ch *Channel) SendAction(msgID string, formData map[string]string) (*Message, error) {
+func (ch *Channel) SendAction(ctx context.Context, msgID string, formData map[string]string) (*Message, error) {
 	switch {
 	case msgID == "":
 		return nil, errors.New("message ID is empty")
@@ -476,6 +564,6 @@ func (ch *Channel) SendAction(msgID string, formData map[string]string) (*Messag
 
 	var resp messageResponse
 
-	err := ch.client.makeRequest(http.MethodPost, p, nil, data, &resp)
+	err := ch.client.makeRequest(ctx, http.MethodPost, p, nil, data, &resp)
 	return resp.Message, err
 }
@@ -1,6 +1,9 @@
 package stream_chat // nolint: golint
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -8,39 +11,92 @@ import (
 )
 
 func TestClient_PinMessage(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 	userA := randomUser(t, c)
 	userB := randomUser(t, c)
 
 	ch := initChannel(t, c, userA.ID, userB.ID)
-	ch, err := c.CreateChannel(ch.Type, ch.ID, userA.ID, nil)
+	ch, err := c.CreateChannel(ctx, ch.Type, ch.ID, userA.ID, nil)
 	require.NoError(t, err)
 
 	msg := &Message{Text: "test message"}
-	msg, err = ch.SendMessage(msg, userB.ID)
+	msg, err = ch.SendMessage(ctx, msg, userB.ID)
 	require.NoError(t, err)
 
-	msg, err = c.PinMessage(msg.ID, userA.ID, nil)
+	msg, err = c.PinMessage(ctx, msg.ID, userA.ID, nil)
 	require.NoError(t, err)
 	require.NotZero(t, msg.PinnedAt)
 	require.NotZero(t, msg.PinnedBy)
 	require.Equal(t, userA.ID, msg.PinnedBy.ID)
 
-	msg, err = c.UnPinMessage(msg.ID, userA.ID)
+	msg, err = c.UnPinMessage(ctx, msg.ID, userA.ID)
 	require.NoError(t, err)
 	require.Zero(t, msg.PinnedAt)
 	require.Zero(t, msg.PinnedBy)
 
 	expireAt := time.Now().Add(3 * time.Second)
-	msg, err = c.PinMessage(msg.ID, userA.ID, &expireAt)
+	msg, err = c.PinMessage(ctx, msg.ID, userA.ID, &expireAt)
 	require.NoError(t, err)
 	require.NotZero(t, msg.PinnedAt)
 	require.NotZero(t, msg.PinnedBy)
 	require.Equal(t, userA.ID, msg.PinnedBy.ID)
 
 	time.Sleep(3 * time.Second)
-	msg, err = c.GetMessage(msg.ID)
+	msg, err = c.GetMessage(ctx, msg.ID)
 	require.NoError(t, err)
 	require.Zero(t, msg.PinnedAt)
 	require.Zero(t, msg.PinnedBy)
 }
+
+func TestChannel_SendMessageExisting(t *testing.T) {
+	var fetched int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			fetched++
+			_, _ = w.Write([]byte(`{"message":{"id":"msg","cid":"messaging:general","text":"first","user":{"id":"tommaso"}}}`))
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"code":4,"message":"SendMessage failed with error: \"duplicate\"","StatusCode":400}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("key", "secret", WithBaseURL(srv.URL))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	msg, err := c.Channel("messaging", "general").SendMessage(ctx, &Message{ID: "msg", Text: "second"}, "tommaso")
+	require.NoError(t, err, "the input error is matched by code")
+	require.Equal(t, "first", msg.Text)
+
+	_, err = c.Channel("messaging", "random").SendMessage(ctx, &Message{ID: "msg", Text: "second"}, "tommaso")
+	require.True(t, IsInputError(err), "the message is in another channel")
+
+	_, err = c.Channel("messaging", "general").SendMessage(ctx, &Message{ID: "msg", Text: "second"}, "thierry")
+	require.True(t, IsInputError(err), "the message is by another user")
+
+	_, err = c.Channel("messaging", "general").SendMessage(ctx, &Message{Text: "second"}, "tommaso")
+	require.True(t, IsInputError(err))
+	require.Equal(t, 3, fetched, "messages without ID are not fetched")
+}
+
+func TestClient_UpdateMessageID(t *testing.T) {
+	ctx := context.Background()
+
+	c := initClient(t)
+	user := randomUser(t, c)
+	ch := initChannel(t, c, user.ID)
+
+	msg, err := ch.SendMessage(ctx, &Message{Text: "first"}, user.ID)
+	require.NoError(t, err)
+
+	msg.Text = "updated"
+	updated, err := c.UpdateMessage(ctx, msg, msg.ID)
+	require.NoError(t, err)
+	require.Equal(t, "updated", updated.Text)
+
+	_, err = c.UpdateMessage(ctx, &Message{ID: "other", Text: "updated", User: user}, msg.ID)
+	require.EqualError(t, err, "message ID other does not match "+msg.ID)
+}
@@ -0,0 +1,37 @@
+package stream_chat // nolint: golint
+
+import (
+	"net/http"
+	"time"
+)
+
+// MetricsCollector receives measurements of the API calls made by the client, set with WithMetrics.
+// The endpoint is the API name of the endpoint, like "SendMessage", empty if it is unknown.
+// Implementations must be safe for concurrent use. The streamprom package provides one
+// exporting Prometheus metrics.
+type MetricsCollector interface {
+	// ObserveRequest is called once per API call, after its last attempt. statusCode is the
+	// status of the last response, zero if no response was received, and duration includes the
+	// retries.
+	ObserveRequest(endpoint, method string, statusCode int, duration time.Duration)
+	// ObserveRetry is called every time a failed attempt is retried.
+	ObserveRetry(endpoint, method string)
+	// ObserveRateLimit is called with the rate limit headers of every response.
+	ObserveRateLimit(endpoint string, limit RateLimitInfo)
+}
+
+// WithMetrics sets the collector receiving the metrics of the API calls.
+func WithMetrics(collector MetricsCollector) ClientOption {
+	return func(o *clientOptions) {
+		o.metrics = collector
+	}
+}
+
+// observeRequest reports an API call started at start to the metrics collector.
+func (c *Client) observeRequest(r *http.Request, start time.Time, resp *http.Response) {
+	statusCode := 0
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	c.metrics.ObserveRequest(endpointFromContext(r.Context()), r.Method, statusCode, time.Since(start))
+}
@@ -0,0 +1,89 @@
+package stream_chat // nolint: golint
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type observedRequest struct {
+	endpoint, method string
+	statusCode       int
+}
+
+type recordingMetrics struct {
+	mu         sync.Mutex
+	requests   []observedRequest
+	retries    []string
+	rateLimits map[string]RateLimitInfo
+}
+
+func (m *recordingMetrics) ObserveRequest(endpoint, method string, statusCode int, _ time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = append(m.requests, observedRequest{endpoint, method, statusCode})
+}
+
+func (m *recordingMetrics) ObserveRetry(endpoint, method string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries = append(m.retries, method+" "+endpoint)
+}
+
+func (m *recordingMetrics) ObserveRateLimit(endpoint string, limit RateLimitInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.rateLimits == nil {
+		m.rateLimits = make(map[string]RateLimitInfo)
+	}
+	m.rateLimits[endpoint] = limit
+}
+
+func TestClient_Metrics(t *testing.T) {
+	ctx := context.Background()
+
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "60")
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		w.Header().Set("X-RateLimit-Reset", "1609459200")
+		switch {
+		case r.URL.Path == "/messages/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case atomic.AddInt32(&calls, 1) == 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			_, _ = w.Write([]byte(`{"app":{"name":"test"}}`))
+		}
+	}))
+	defer srv.Close()
+
+	metrics := &recordingMetrics{}
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	c, err := NewClient("key", "secret", WithBaseURL(srv.URL), WithRetryPolicy(policy), WithMetrics(metrics))
+	require.NoError(t, err)
+
+	_, err = c.GetAppConfig(ctx)
+	require.NoError(t, err)
+	_, err = c.GetMessage(ctx, "missing")
+	require.True(t, IsNotFound(err))
+
+	srv.Close()
+	err = c.DeleteChannelType(ctx, "unreachable")
+	require.Error(t, err)
+
+	require.Equal(t, []observedRequest{
+		{"GetApp", http.MethodGet, http.StatusOK},
+		{"GetMessage", http.MethodGet, http.StatusNotFound},
+		{"DeleteChannelType", http.MethodDelete, 0},
+	}, metrics.requests)
+	require.Equal(t, []string{"GET GetApp", "DELETE DeleteChannelType", "DELETE DeleteChannelType"}, metrics.retries)
+	require.Equal(t, RateLimitInfo{Limit: 60, Remaining: 42, Reset: 1609459200}, metrics.rateLimits["GetApp"])
+}
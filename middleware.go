@@ -0,0 +1,52 @@
+package stream_chat // nolint: golint
+
+import (
+	"errors"
+	"net/http"
+)
+
+// RequestHandler sends an API request and returns its response.
+type RequestHandler func(r *http.Request) (*http.Response, error)
+
+// Middleware wraps a RequestHandler to add behavior around every request sent by the client,
+// for example logging, header injection or metrics.
+//
+// A middleware may change the request before passing it to next and inspect or replace the
+// response afterwards. It can also short-circuit the chain by returning a response or an error
+// without calling next. Middlewares run for every attempt of a request, so a retried request
+// goes through the chain again. The innermost handler sends the request with Client.HTTP.
+type Middleware func(next RequestHandler) RequestHandler
+
+// WithMiddleware appends middlewares to the client chain. Middlewares run in the order they are
+// added: the first one sees the request first and the response last.
+func WithMiddleware(middlewares ...Middleware) ClientOption {
+	return func(o *clientOptions) {
+		o.middlewares = append(o.middlewares, middlewares...)
+	}
+}
+
+// roundTrip sends the request through the middleware chain.
+func (c *Client) roundTrip(r *http.Request) (*http.Response, error) {
+	handler := RequestHandler(c.HTTP.Do)
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		handler = c.middlewares[i](handler)
+	}
+
+	resp, err := handler(r)
+	if resp == nil && err == nil {
+		return nil, errors.New("chat-client: middleware returned neither a response nor an error")
+	}
+	if resp != nil {
+		// responses built by a middleware may lack what the transport always sets
+		if resp.Body == nil {
+			resp.Body = http.NoBody
+		}
+		if resp.Header == nil {
+			resp.Header = make(http.Header)
+		}
+		if resp.Request == nil {
+			resp.Request = r
+		}
+	}
+	return resp, err
+}
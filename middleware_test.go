@@ -0,0 +1,120 @@
+package stream_chat // nolint: golint
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Middleware(t *testing.T) {
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Tenant", r.Header.Get("X-Tenant"))
+		_, _ = w.Write([]byte(`{"file":"https://cdn/file.txt"}`))
+	}))
+	defer srv.Close()
+
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next RequestHandler) RequestHandler {
+			return func(r *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" request")
+				resp, err := next(r)
+				calls = append(calls, name+" response")
+				return resp, err
+			}
+		}
+	}
+	inject := func(next RequestHandler) RequestHandler {
+		return func(r *http.Request) (*http.Response, error) {
+			r.Header.Set("X-Tenant", "acme")
+			resp, err := next(r)
+			require.NoError(t, err)
+			require.Equal(t, "acme", resp.Header.Get("X-Tenant"))
+			return resp, err
+		}
+	}
+	c, err := NewClient("key", "secret", WithBaseURL(srv.URL),
+		WithMiddleware(trace("first")), WithMiddleware(trace("second"), inject))
+	require.NoError(t, err)
+
+	_, err = c.GetAppConfig(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"first request", "second request", "second response", "first response"}, calls)
+
+	t.Run("file uploads go through the chain", func(t *testing.T) {
+		calls = nil
+
+		loc, err := c.Channel("messaging", "general").SendFile(ctx, SendFileRequest{
+			Reader:   strings.NewReader("hello"),
+			FileName: "file.txt",
+			User:     &User{ID: "user"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "https://cdn/file.txt", loc)
+		require.Len(t, calls, 4)
+	})
+}
+
+func TestClient_MiddlewareShortCircuit(t *testing.T) {
+	ctx := context.Background()
+
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	// fail the first attempt, answer the second one without calling the server
+	attempts := 0
+	fault := func(next RequestHandler) RequestHandler {
+		return func(r *http.Request) (*http.Response, error) {
+			attempts++
+			status, body := http.StatusServiceUnavailable, `{"message":"injected fault"}`
+			if attempts > 1 {
+				status, body = http.StatusOK, `{"app":{"name":"fake"}}`
+			}
+			return &http.Response{
+				StatusCode: status,
+				Header:     http.Header{},
+				Body:       ioutil.NopCloser(strings.NewReader(body)),
+				Request:    r,
+			}, nil
+		}
+	}
+	c, err := NewClient("key", "secret", WithBaseURL("http://127.0.0.1:0"), WithRetryPolicy(policy), WithMiddleware(fault))
+	require.NoError(t, err)
+
+	app, err := c.GetAppConfig(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "fake", app.Name)
+	require.Equal(t, 2, attempts)
+}
+
+func TestClient_MiddlewareResponseWithoutBody(t *testing.T) {
+	ctx := context.Background()
+
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	attempts := 0
+	fault := func(next RequestHandler) RequestHandler {
+		return func(r *http.Request) (*http.Response, error) {
+			attempts++
+			if attempts == 1 {
+				return &http.Response{StatusCode: http.StatusServiceUnavailable}, nil
+			}
+			return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}, nil
+		}
+	}
+	c, err := NewClient("key", "secret", WithBaseURL("http://127.0.0.1:0"), WithRetryPolicy(policy), WithMiddleware(fault))
+	require.NoError(t, err)
+
+	require.NoError(t, c.DeleteChannelType(ctx, "fake"), "the retried response without body is drained")
+	require.Equal(t, 2, attempts)
+
+	_, err = c.GetAppConfig(ctx)
+	require.Error(t, err, "an empty body is not valid JSON")
+}
@@ -0,0 +1,218 @@
+package stream_chat // nolint: golint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+)
+
+// ErrUnknownApp is returned by ClientPool for apps and tenants which are not configured.
+var ErrUnknownApp = errors.New("chat-client: unknown app")
+
+// PoolAppConfig is the configuration of a Stream app in a ClientPool.
+// Environment variables like $EU_API_SECRET in the key and secret are expanded.
+type PoolAppConfig struct {
+	APIKey    string `json:"api_key"`
+	APISecret string `json:"api_secret"`
+	// BaseURL is the URL of the API, the edge endpoint if empty.
+	BaseURL string `json:"base_url,omitempty"`
+	// Timeout is the request timeout, either a duration like "10s" or a number of seconds.
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// ClientPoolConfig is the configuration of the apps of a ClientPool.
+type ClientPoolConfig struct {
+	// Apps are the configurations of the apps by name.
+	Apps map[string]PoolAppConfig `json:"apps"`
+	// Tenants map tenant IDs to app names.
+	Tenants map[string]string `json:"tenants,omitempty"`
+}
+
+// LoadClientPoolConfig reads a ClientPoolConfig from a JSON file:
+//
+//	{
+//		"apps": {
+//			"eu": {"api_key": "key", "api_secret": "$EU_API_SECRET"},
+//			"us": {"api_key": "key", "api_secret": "$US_API_SECRET", "timeout": "10s"}
+//		},
+//		"tenants": {"acme": "eu", "globex": "us"}
+//	}
+func LoadClientPoolConfig(path string) (*ClientPoolConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config ClientPoolConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid client pool config %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// ClientPool holds the clients of several Stream apps, by app name and tenant ID.
+// The clients share a single HTTP transport. Apps and tenants can be added and removed
+// while the pool is in use.
+type ClientPool struct {
+	options    []ClientOption
+	httpClient *http.Client
+
+	mu      sync.RWMutex
+	clients map[string]*Client
+	tenants map[string]string
+}
+
+// NewClientPool creates an empty pool. The options are applied to every client of the pool.
+func NewClientPool(options ...ClientOption) *ClientPool {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	return &ClientPool{
+		options:    options,
+		httpClient: &http.Client{Timeout: defaultTimeout, Transport: transport},
+		clients:    make(map[string]*Client),
+		tenants:    make(map[string]string),
+	}
+}
+
+// NewClientPoolFromConfig creates a pool with the apps and tenants of the config.
+func NewClientPoolFromConfig(config *ClientPoolConfig, options ...ClientOption) (*ClientPool, error) {
+	p := NewClientPool(options...)
+	if err := p.Load(config); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *ClientPool) newClient(name string, config PoolAppConfig) (*Client, error) {
+	options := append([]ClientOption{WithHTTPClient(p.httpClient)}, p.options...)
+	if config.BaseURL != "" {
+		options = append(options, WithBaseURL(config.BaseURL))
+	}
+	if config.Timeout != "" {
+		timeout, err := parseTimeout(config.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("app %s: invalid timeout: %w", name, err)
+		}
+		options = append(options, WithTimeout(timeout))
+	}
+
+	c, err := NewClient(os.ExpandEnv(config.APIKey), os.ExpandEnv(config.APISecret), options...)
+	if err != nil {
+		return nil, fmt.Errorf("app %s: %w", name, err)
+	}
+	return c, nil
+}
+
+// Load replaces the apps and tenants of the pool with the ones of the config.
+// Nothing is changed if the config is not valid.
+func (p *ClientPool) Load(config *ClientPoolConfig) error {
+	clients := make(map[string]*Client, len(config.Apps))
+	for name, app := range config.Apps {
+		c, err := p.newClient(name, app)
+		if err != nil {
+			return err
+		}
+		clients[name] = c
+	}
+
+	tenants := make(map[string]string, len(config.Tenants))
+	for tenant, app := range config.Tenants {
+		if _, ok := clients[app]; !ok {
+			return fmt.Errorf("%w: %s, for tenant %s", ErrUnknownApp, app, tenant)
+		}
+		tenants[tenant] = app
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clients, p.tenants = clients, tenants
+	return nil
+}
+
+// AddApp adds an app to the pool, replacing the app with the same name.
+func (p *ClientPool) AddApp(name string, config PoolAppConfig) (*Client, error) {
+	c, err := p.newClient(name, config)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clients[name] = c
+	return c, nil
+}
+
+// RemoveApp removes an app and its tenants from the pool.
+// Requests in flight with its client complete normally.
+func (p *ClientPool) RemoveApp(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.clients, name)
+	for tenant, app := range p.tenants {
+		if app == name {
+			delete(p.tenants, tenant)
+		}
+	}
+}
+
+// SetTenant routes the calls for the tenant to the app.
+func (p *ClientPool) SetTenant(tenantID, app string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.clients[app]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownApp, app)
+	}
+	p.tenants[tenantID] = app
+	return nil
+}
+
+// RemoveTenant removes the route of the tenant.
+func (p *ClientPool) RemoveTenant(tenantID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.tenants, tenantID)
+}
+
+// App returns the client of the app.
+func (p *ClientPool) App(name string) (*Client, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	c, ok := p.clients[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownApp, name)
+	}
+	return c, nil
+}
+
+// Tenant returns the client of the app of the tenant.
+func (p *ClientPool) Tenant(tenantID string) (*Client, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	app, ok := p.tenants[tenantID]
+	if !ok {
+		return nil, fmt.Errorf("%w: no app for tenant %s", ErrUnknownApp, tenantID)
+	}
+	return p.clients[app], nil
+}
+
+// Apps returns the names of the apps of the pool, sorted.
+func (p *ClientPool) Apps() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	names := make([]string, 0, len(p.clients))
+	for name := range p.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
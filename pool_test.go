@@ -0,0 +1,90 @@
+package stream_chat // nolint: golint
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientPool(t *testing.T) {
+	var apiKeys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiKeys = append(apiKeys, r.URL.Query().Get("api_key"))
+		_, _ = w.Write([]byte(`{"message":{"id":"msg"}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	dir, err := ioutil.TempDir("", "pool")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "apps.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{
+		"apps": {
+			"eu": {"api_key": "eu-key", "api_secret": "$POOL_TEST_SECRET", "base_url": "`+srv.URL+`"},
+			"us": {"api_key": "us-key", "api_secret": "us-secret", "base_url": "`+srv.URL+`", "timeout": "10s"}
+		},
+		"tenants": {"acme": "eu", "globex": "us"}
+	}`), 0o600))
+	require.NoError(t, os.Setenv("POOL_TEST_SECRET", "eu-secret"))
+	t.Cleanup(func() { os.Unsetenv("POOL_TEST_SECRET") })
+
+	config, err := LoadClientPoolConfig(path)
+	require.NoError(t, err)
+	pool, err := NewClientPoolFromConfig(config, WithUserAgentSuffix("pool"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"eu", "us"}, pool.Apps())
+
+	eu, err := pool.App("eu")
+	require.NoError(t, err)
+	require.Equal(t, []byte("eu-secret"), eu.apiSecret, "environment variables are expanded")
+	us, err := pool.App("us")
+	require.NoError(t, err)
+	require.Equal(t, 10*time.Second, us.HTTP.Timeout)
+	require.Same(t, eu.HTTP.Transport, us.HTTP.Transport, "the transport is shared")
+
+	ctx := context.Background()
+	for _, tenant := range []string{"acme", "globex"} {
+		c, err := pool.Tenant(tenant)
+		require.NoError(t, err)
+		_, err = c.GetMessage(ctx, "msg")
+		require.NoError(t, err)
+	}
+	require.Equal(t, []string{"eu-key", "us-key"}, apiKeys)
+
+	t.Run("runtime changes", func(t *testing.T) {
+		_, err := pool.AddApp("asia", PoolAppConfig{APIKey: "asia-key", APISecret: "asia-secret", BaseURL: srv.URL})
+		require.NoError(t, err)
+		require.NoError(t, pool.SetTenant("acme", "asia"))
+		c, err := pool.Tenant("acme")
+		require.NoError(t, err)
+		require.Equal(t, "asia-key", c.apiKey)
+
+		require.True(t, errors.Is(pool.SetTenant("initech", "africa"), ErrUnknownApp))
+
+		pool.RemoveApp("us")
+		_, err = pool.App("us")
+		require.True(t, errors.Is(err, ErrUnknownApp))
+		_, err = pool.Tenant("globex")
+		require.True(t, errors.Is(err, ErrUnknownApp), "the tenants of removed apps are removed")
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		err := pool.Load(&ClientPoolConfig{
+			Apps:    map[string]PoolAppConfig{"eu": {APIKey: "eu-key", APISecret: "eu-secret"}},
+			Tenants: map[string]string{"acme": "us"},
+		})
+		require.True(t, errors.Is(err, ErrUnknownApp))
+		require.Equal(t, []string{"asia", "eu"}, pool.Apps(), "the pool is unchanged")
+
+		err = pool.Load(&ClientPoolConfig{Apps: map[string]PoolAppConfig{"eu": {APIKey: "eu-key"}}})
+		require.EqualError(t, err, "app eu: API secret is empty")
+	})
+}
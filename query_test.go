@@ -1,6 +1,7 @@
 package stream_chat // nolint: golint
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -10,6 +11,8 @@ import (
 )
 
 func TestClient_QueryUsers(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 
 	const n = 4
@@ -17,14 +20,14 @@ func TestClient_QueryUsers(t *testing.T) {
 	defer func() {
 		for _, id := range ids {
 			if id != "" {
-				_ = c.DeleteUser(id, nil)
+				_ = c.DeleteUser(ctx, id, nil)
 			}
 		}
 	}()
 
 	for i := n - 1; i > -1; i-- {
 		u := &User{ID: randomString(30), ExtraData: map[string]interface{}{"order": n - i - 1}}
-		_, err := c.UpsertUser(u)
+		_, err := c.UpsertUser(ctx, u)
 		require.NoError(t, err)
 		ids[i] = u.ID
 		time.Sleep(200 * time.Millisecond)
@@ -32,7 +35,7 @@ func TestClient_QueryUsers(t *testing.T) {
 
 	t.Parallel()
 	t.Run("Query all", func(tt *testing.T) {
-		results, err := c.QueryUsers(&QueryOption{
+		results, err := c.QueryUsers(ctx, &QueryOption{
 			Filter: map[string]interface{}{
 				"id": map[string]interface{}{
 					"$in": ids,
@@ -47,7 +50,7 @@ func TestClient_QueryUsers(t *testing.T) {
 	t.Run("Query with offset/limit", func(tt *testing.T) {
 		offset := 1
 
-		results, err := c.QueryUsers(
+		results, err := c.QueryUsers(ctx,
 			&QueryOption{
 				Filter: map[string]interface{}{
 					"id": map[string]interface{}{
@@ -68,16 +71,18 @@ func TestClient_QueryUsers(t *testing.T) {
 }
 
 func TestClient_QueryChannels(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 	ch := initChannel(t, c)
 
-	_, err := ch.SendMessage(&Message{Text: "abc"}, "some")
+	_, err := ch.SendMessage(ctx, &Message{Text: "abc"}, "some")
 	require.NoError(t, err)
-	_, err = ch.SendMessage(&Message{Text: "abc"}, "some")
+	_, err = ch.SendMessage(ctx, &Message{Text: "abc"}, "some")
 	require.NoError(t, err)
 
 	messageLimit := 1
-	got, err := c.QueryChannels(&QueryOption{
+	got, err := c.QueryChannels(ctx, &QueryOption{
 		Filter: map[string]interface{}{
 			"id": map[string]interface{}{
 				"$eq": ch.ID,
@@ -92,6 +97,8 @@ func TestClient_QueryChannels(t *testing.T) {
 }
 
 func TestClient_Search(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 
 	user1, user2 := randomUser(t, c), randomUser(t, c)
@@ -100,14 +107,14 @@ func TestClient_Search(t *testing.T) {
 
 	text := randomString(10)
 
-	_, err := ch.SendMessage(&Message{Text: text + " " + randomString(25)}, user1.ID)
+	_, err := ch.SendMessage(ctx, &Message{Text: text + " " + randomString(25)}, user1.ID)
 	require.NoError(t, err)
 
-	_, err = ch.SendMessage(&Message{Text: text + " " + randomString(25)}, user2.ID)
+	_, err = ch.SendMessage(ctx, &Message{Text: text + " " + randomString(25)}, user2.ID)
 	require.NoError(t, err)
 
 	t.Run("Query", func(tt *testing.T) {
-		got, err := c.Search(SearchRequest{Query: text, Filters: map[string]interface{}{
+		got, err := c.Search(ctx, SearchRequest{Query: text, Filters: map[string]interface{}{
 			"members": map[string][]string{
 				"$in": {user1.ID, user2.ID},
 			},
@@ -118,7 +125,7 @@ func TestClient_Search(t *testing.T) {
 		assert.Len(tt, got, 2)
 	})
 	t.Run("Message filters", func(tt *testing.T) {
-		got, err := c.Search(SearchRequest{
+		got, err := c.Search(ctx, SearchRequest{
 			Filters: map[string]interface{}{
 				"members": map[string][]string{
 					"$in": {user1.ID, user2.ID},
@@ -135,7 +142,7 @@ func TestClient_Search(t *testing.T) {
 		assert.Len(tt, got, 2)
 	})
 	t.Run("Query and message filters error", func(tt *testing.T) {
-		_, err := c.Search(SearchRequest{
+		_, err := c.Search(ctx, SearchRequest{
 			Filters: map[string]interface{}{
 				"members": map[string][]string{
 					"$in": {user1.ID, user2.ID},
@@ -151,7 +158,7 @@ func TestClient_Search(t *testing.T) {
 		require.Error(tt, err)
 	})
 	t.Run("Offset and sort error", func(tt *testing.T) {
-		_, err := c.Search(SearchRequest{
+		_, err := c.Search(ctx, SearchRequest{
 			Filters: map[string]interface{}{
 				"members": map[string][]string{
 					"$in": {user1.ID, user2.ID},
@@ -167,7 +174,7 @@ func TestClient_Search(t *testing.T) {
 		require.Error(tt, err)
 	})
 	t.Run("Offset and next error", func(tt *testing.T) {
-		_, err := c.Search(SearchRequest{
+		_, err := c.Search(ctx, SearchRequest{
 			Filters: map[string]interface{}{
 				"members": map[string][]string{
 					"$in": {user1.ID, user2.ID},
@@ -182,6 +189,8 @@ func TestClient_Search(t *testing.T) {
 }
 
 func TestClient_SearchWithFullResponse(t *testing.T) {
+	ctx := context.Background()
+
 	t.Skip()
 	c := initClient(t)
 	ch := initChannel(t, c)
@@ -197,7 +206,7 @@ func TestClient_SearchWithFullResponse(t *testing.T) {
 			userID = user2.ID
 		}
 		messageID := fmt.Sprintf("%d-%s", i, text)
-		_, err := ch.SendMessage(&Message{
+		_, err := ch.SendMessage(ctx, &Message{
 			ID:   messageID,
 			Text: text + " " + randomString(25),
 		}, userID)
@@ -206,7 +215,7 @@ func TestClient_SearchWithFullResponse(t *testing.T) {
 		messageIDs[6-i] = messageID
 	}
 
-	got, err := c.SearchWithFullResponse(SearchRequest{
+	got, err := c.SearchWithFullResponse(ctx, SearchRequest{
 		Query: text,
 		Filters: map[string]interface{}{
 			"members": map[string][]string{
@@ -226,7 +235,7 @@ func TestClient_SearchWithFullResponse(t *testing.T) {
 	for _, result := range got.Results {
 		gotMessageIDs = append(gotMessageIDs, result.Message.ID)
 	}
-	got, err = c.SearchWithFullResponse(SearchRequest{
+	got, err = c.SearchWithFullResponse(ctx, SearchRequest{
 		Query: text,
 		Filters: map[string]interface{}{
 			"members": map[string][]string{
@@ -247,6 +256,8 @@ func TestClient_SearchWithFullResponse(t *testing.T) {
 }
 
 func TestClient_QueryMessageFlags(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 	ch := initChannel(t, c)
 
@@ -257,20 +268,20 @@ func TestClient_QueryMessageFlags(t *testing.T) {
 
 	// send 2 messages
 	text := randomString(10)
-	msg1, err := ch.SendMessage(&Message{Text: text + " " + randomString(25)}, user1.ID)
+	msg1, err := ch.SendMessage(ctx, &Message{Text: text + " " + randomString(25)}, user1.ID)
 	require.NoError(t, err)
-	msg2, err := ch.SendMessage(&Message{Text: text + " " + randomString(25)}, user2.ID)
+	msg2, err := ch.SendMessage(ctx, &Message{Text: text + " " + randomString(25)}, user2.ID)
 	require.NoError(t, err)
 
 	// flag 2 messages
-	err = c.FlagMessage(msg2.ID, user1.ID)
+	err = c.FlagMessage(ctx, msg2.ID, user1.ID)
 	require.NoError(t, err)
 
-	err = c.FlagMessage(msg1.ID, user2.ID)
+	err = c.FlagMessage(ctx, msg1.ID, user2.ID)
 	require.NoError(t, err)
 
 	// both flags show up in this query by channel_cid
-	got, err := c.QueryMessageFlags(&QueryOption{
+	got, err := c.QueryMessageFlags(ctx, &QueryOption{
 		Filter: map[string]interface{}{
 			"channel_cid": map[string][]string{
 				"$in": {ch.cid()},
@@ -281,7 +292,7 @@ func TestClient_QueryMessageFlags(t *testing.T) {
 	assert.Len(t, got, 2)
 
 	// one flag shows up in this query by user_id
-	got, err = c.QueryMessageFlags(&QueryOption{
+	got, err = c.QueryMessageFlags(ctx, &QueryOption{
 		Filter: map[string]interface{}{
 			"user_id": user1.ID,
 		},
@@ -290,13 +301,13 @@ func TestClient_QueryMessageFlags(t *testing.T) {
 	assert.Len(t, got, 1)
 
 	// unflag these 2 messages
-	err = c.UnflagMessage(msg1.ID, user2.ID)
+	err = c.UnflagMessage(ctx, msg1.ID, user2.ID)
 	require.NoError(t, err)
-	err = c.UnflagMessage(msg2.ID, user1.ID)
+	err = c.UnflagMessage(ctx, msg2.ID, user1.ID)
 	require.NoError(t, err)
 
 	// none should show up
-	got, err = c.QueryMessageFlags(&QueryOption{
+	got, err = c.QueryMessageFlags(ctx, &QueryOption{
 		Filter: map[string]interface{}{"channel_cid": ch.cid()},
 	})
 	require.NoError(t, err)
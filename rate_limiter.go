@@ -0,0 +1,156 @@
+package stream_chat // nolint: golint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// rateLimitWindow is the length of the API rate limit windows.
+const rateLimitWindow = time.Minute
+
+// ErrRateLimitExceeded is returned by the client side rate limiter in RateLimiterFailFast mode
+// when the quota of an endpoint is exhausted.
+var ErrRateLimitExceeded = errors.New("chat-client: rate limit exceeded")
+
+// RateLimiterMode defines what happens to calls when the quota of an endpoint is exhausted.
+type RateLimiterMode int
+
+const (
+	// RateLimiterBlock makes calls wait until the quota is available again or the context is done.
+	RateLimiterBlock RateLimiterMode = iota
+	// RateLimiterFailFast makes calls fail immediately with ErrRateLimitExceeded.
+	RateLimiterFailFast
+)
+
+// rateLimitBucket holds the quota of an endpoint. Its tokens are refilled when the
+// current rate limit window expires.
+type rateLimitBucket struct {
+	limit     int64
+	remaining int64
+	reset     time.Time
+}
+
+// take consumes a token, or returns how long to wait for the next window when none is left.
+func (b *rateLimitBucket) take(now time.Time) time.Duration {
+	if !now.Before(b.reset) {
+		b.remaining = b.limit
+		b.reset = now.Add(rateLimitWindow)
+	}
+
+	if b.remaining > 0 {
+		b.remaining--
+		return 0
+	}
+	return b.reset.Sub(now)
+}
+
+// update syncs the bucket with the quota reported by the server.
+func (b *rateLimitBucket) update(info RateLimitInfo) {
+	reset := info.ResetTime()
+	b.limit = info.Limit
+
+	switch {
+	case reset.After(b.reset):
+		b.remaining = info.Remaining
+		b.reset = reset
+	case reset.Equal(b.reset) && info.Remaining < b.remaining:
+		// requests in flight are already accounted locally, keep the lowest count
+		b.remaining = info.Remaining
+	}
+}
+
+type rateLimiter struct {
+	mode RateLimiterMode
+
+	mu      sync.Mutex
+	buckets map[string]*rateLimitBucket
+}
+
+func newRateLimiter(mode RateLimiterMode, limits RateLimitsMap) *rateLimiter {
+	l := &rateLimiter{
+		mode:    mode,
+		buckets: make(map[string]*rateLimitBucket, len(limits)),
+	}
+	for endpoint, info := range limits {
+		l.update(endpoint, info)
+	}
+	return l
+}
+
+// wait blocks until a call to endpoint is allowed. Endpoints without known quota are not limited.
+func (l *rateLimiter) wait(ctx context.Context, endpoint string) error {
+	for {
+		l.mu.Lock()
+		b, ok := l.buckets[endpoint]
+		if !ok {
+			l.mu.Unlock()
+			return nil
+		}
+		d := b.take(time.Now())
+		l.mu.Unlock()
+
+		switch {
+		case d <= 0:
+			return nil
+		case l.mode == RateLimiterFailFast:
+			return fmt.Errorf("%w: endpoint %s, retry in %s", ErrRateLimitExceeded, endpoint, d)
+		}
+
+		if err := sleepContext(ctx, d); err != nil {
+			return err
+		}
+	}
+}
+
+func (l *rateLimiter) update(endpoint string, info RateLimitInfo) {
+	if endpoint == "" {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[endpoint]
+	if !ok {
+		b = &rateLimitBucket{}
+		l.buckets[endpoint] = b
+	}
+	b.update(info)
+}
+
+// EnableRateLimiter makes the client throttle its own calls to stay within the API rate limits.
+// The quota of every endpoint is fetched with GetRateLimits and kept up to date with the
+// rate limit headers of every response. Depending on mode, calls exceeding the quota either
+// wait for the next rate limit window or fail with ErrRateLimitExceeded. It is safe to call
+// while the client is in use: the requests in flight keep the limiter they started with.
+func (c *Client) EnableRateLimiter(ctx context.Context, mode RateLimiterMode) error {
+	limits, err := c.GetRateLimits(ctx, WithServerSide())
+	if err != nil {
+		return err
+	}
+
+	c.storeRateLimiter(newRateLimiter(mode, limits.ServerSide))
+	return nil
+}
+
+// DisableRateLimiter turns off the client side rate limiter.
+// Like EnableRateLimiter, it is safe to call while the client is in use.
+func (c *Client) DisableRateLimiter() {
+	c.storeRateLimiter(nil)
+}
+
+// loadRateLimiter returns the rate limiter of the client, nil if it is disabled.
+func (c *Client) loadRateLimiter() *rateLimiter {
+	c.rateLimiterMu.RLock()
+	defer c.rateLimiterMu.RUnlock()
+	return c.rateLimiter
+}
+
+func (c *Client) storeRateLimiter(l *rateLimiter) {
+	c.rateLimiterMu.Lock()
+	defer c.rateLimiterMu.Unlock()
+	c.rateLimiter = l
+}
@@ -0,0 +1,129 @@
+package stream_chat // nolint: golint
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitBucket(t *testing.T) {
+	now := time.Unix(1000, 0)
+	b := &rateLimitBucket{}
+	b.update(RateLimitInfo{Limit: 2, Remaining: 1, Reset: now.Add(10 * time.Second).Unix()})
+
+	require.Zero(t, b.take(now))
+	require.Equal(t, 10*time.Second, b.take(now))
+
+	// older info for the same window must not give back quota
+	b.update(RateLimitInfo{Limit: 2, Remaining: 1, Reset: now.Add(10 * time.Second).Unix()})
+	require.Equal(t, 10*time.Second, b.take(now))
+
+	// the next window refills the bucket
+	require.Zero(t, b.take(now.Add(10*time.Second)))
+	require.Zero(t, b.take(now.Add(10*time.Second)))
+	require.Equal(t, time.Minute, b.take(now.Add(10*time.Second)))
+}
+
+func TestClient_RateLimiter(t *testing.T) {
+	ctx := context.Background()
+
+	var appCalls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reset := strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10)
+
+		switch r.URL.Path {
+		case "/rate_limits":
+			require.Equal(t, "true", r.URL.Query().Get("server_side"))
+			_, _ = w.Write([]byte(`{"server_side":{"GetApp":{"limit":2,"remaining":2,"reset":` + reset + `}}}`))
+		case "/app":
+			atomic.AddInt32(&appCalls, 1)
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("key", "secret", WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	require.NoError(t, c.EnableRateLimiter(ctx, RateLimiterFailFast))
+
+	for i := 0; i < 2; i++ {
+		_, err = c.GetAppConfig(ctx)
+		require.NoError(t, err)
+	}
+
+	_, err = c.GetAppConfig(ctx)
+	require.True(t, errors.Is(err, ErrRateLimitExceeded), err)
+	require.EqualValues(t, 2, atomic.LoadInt32(&appCalls))
+
+	// endpoints without known quota are not limited
+	_, err = c.ListCommands(ctx)
+	require.NoError(t, err)
+
+	t.Run("blocking mode waits for the context", func(t *testing.T) {
+		c.loadRateLimiter().mode = RateLimiterBlock
+
+		ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+
+		_, err = c.GetAppConfig(ctx)
+		require.True(t, errors.Is(err, context.DeadlineExceeded), err)
+	})
+
+	t.Run("headers refresh the quota", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-RateLimit-Limit", "10")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10))
+			_, _ = w.Write([]byte(`{}`))
+		}))
+		defer srv.Close()
+
+		c, err := NewClient("key", "secret", WithBaseURL(srv.URL))
+		require.NoError(t, err)
+		c.storeRateLimiter(newRateLimiter(RateLimiterFailFast, nil))
+
+		_, err = c.GetAppConfig(ctx)
+		require.NoError(t, err)
+
+		_, err = c.GetAppConfig(ctx)
+		require.True(t, errors.Is(err, ErrRateLimitExceeded), err)
+	})
+}
+
+func TestClient_RateLimiterSwitchedInUse(t *testing.T) {
+	ctx := context.Background()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("key", "secret", WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				_, _ = c.GetAppConfig(ctx)
+			}
+		}()
+	}
+	for i := 0; i < 5; i++ {
+		require.NoError(t, c.EnableRateLimiter(ctx, RateLimiterBlock))
+		c.DisableRateLimiter()
+	}
+	wg.Wait()
+}
@@ -1,8 +1,10 @@
 package stream_chat // nolint: golint
 
 import (
+	"context"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -26,6 +28,25 @@ func (i RateLimitInfo) ResetTime() time.Time {
 	return time.Unix(i.Reset, 0)
 }
 
+// NewRateLimitFromHeaders returns the RateLimitInfo described by the X-RateLimit-* headers
+// of a response, or nil if they are missing or malformed.
+func NewRateLimitFromHeaders(headers http.Header) *RateLimitInfo {
+	limit, err := strconv.ParseInt(headers.Get("X-RateLimit-Limit"), 10, 64)
+	if err != nil {
+		return nil
+	}
+	remaining, err := strconv.ParseInt(headers.Get("X-RateLimit-Remaining"), 10, 64)
+	if err != nil {
+		return nil
+	}
+	reset, err := strconv.ParseInt(headers.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	return &RateLimitInfo{Limit: limit, Remaining: remaining, Reset: reset}
+}
+
 // GetRateLimitsResponse is the response of the Client.GetRateLimits call. It includes, if present, the rate
 // limits for the supported platforms, namely server-side, Android, iOS, and web.
 type GetRateLimitsResponse struct {
@@ -83,7 +104,7 @@ func WithEndpoints(endpoints ...string) GetRateLimitsOption {
 
 // GetRateLimits returns the current rate limit quotas and usage. If no options are passed, all the limits
 // for all platforms are returned.
-func (c *Client) GetRateLimits(options ...GetRateLimitsOption) (GetRateLimitsResponse, error) {
+func (c *Client) GetRateLimits(ctx context.Context, options ...GetRateLimitsOption) (GetRateLimitsResponse, error) {
 	rlParams := getRateLimitsParams{}
 	for _, opt := range options {
 		opt(&rlParams)
@@ -107,7 +128,7 @@ func (c *Client) GetRateLimits(options ...GetRateLimitsOption) (GetRateLimitsRes
 	}
 
 	var resp GetRateLimitsResponse
-	err := c.makeRequest(http.MethodGet, "rate_limits", params, nil, &resp)
+	err := c.makeRequest(ctx, http.MethodGet, "rate_limits", params, nil, &resp)
 	if err != nil {
 		return GetRateLimitsResponse{}, err
 	}
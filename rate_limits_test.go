@@ -1,16 +1,19 @@
 package stream_chat // nolint: golint
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 )
 
 func TestClient_GetRateLimits(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 
 	t.Run("get all limits", func(t *testing.T) {
-		limits, err := c.GetRateLimits()
+		limits, err := c.GetRateLimits(ctx)
 		require.NoError(t, err)
 		require.NotEmpty(t, limits.Android)
 		require.NotEmpty(t, limits.Web)
@@ -19,7 +22,7 @@ func TestClient_GetRateLimits(t *testing.T) {
 	})
 
 	t.Run("get only a single platform", func(t *testing.T) {
-		limits, err := c.GetRateLimits(WithServerSide())
+		limits, err := c.GetRateLimits(ctx, WithServerSide())
 		require.NoError(t, err)
 		require.Empty(t, limits.Android)
 		require.Empty(t, limits.Web)
@@ -28,7 +31,7 @@ func TestClient_GetRateLimits(t *testing.T) {
 	})
 
 	t.Run("get only a few endpoints", func(t *testing.T) {
-		limits, err := c.GetRateLimits(
+		limits, err := c.GetRateLimits(ctx,
 			WithServerSide(),
 			WithAndroid(),
 			WithEndpoints(
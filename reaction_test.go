@@ -1,6 +1,7 @@
 package stream_chat // nolint: golint
 
 import (
+	"context"
 	"log"
 	"testing"
 
@@ -9,6 +10,8 @@ import (
 )
 
 func ExampleChannel_SendReaction() {
+	ctx := context.Background()
+
 	channel := &Channel{}
 	msgID := "123"
 	userID := "bob-1"
@@ -17,17 +20,19 @@ func ExampleChannel_SendReaction() {
 		Type:      "love",
 		ExtraData: map[string]interface{}{"my_custom_field": 123},
 	}
-	_, err := channel.SendReaction(reaction, msgID, userID)
+	_, err := channel.SendReaction(ctx, reaction, msgID, userID)
 	if err != nil {
 		log.Fatalf("Found Error: %v", err)
 	}
 }
 
 func TestChannel_SendReaction(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 	ch := initChannel(t, c)
 	defer func() {
-		require.NoError(t, ch.Delete(), "delete channel")
+		require.NoError(t, ch.Delete(ctx), "delete channel")
 	}()
 
 	user := randomUser(t, c)
@@ -35,12 +40,12 @@ func TestChannel_SendReaction(t *testing.T) {
 		Text: "test message",
 		User: user,
 	}
-	msg, err := ch.SendMessage(msg, user.ID)
+	msg, err := ch.SendMessage(ctx, msg, user.ID)
 	require.NoError(t, err, "send message")
 
 	reaction := Reaction{Type: "love"}
 
-	msg, err = ch.SendReaction(&reaction, msg.ID, user.ID)
+	msg, err = ch.SendReaction(ctx, &reaction, msg.ID, user.ID)
 	require.NoError(t, err, "send reaction")
 
 	assert.Equal(t, 1, msg.ReactionCounts[reaction.Type], "reaction count", reaction)
@@ -60,10 +65,12 @@ func reactionExistsCondition(reactions []*Reaction, searchType string) func() bo
 }
 
 func TestChannel_DeleteReaction(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 	ch := initChannel(t, c)
 	defer func() {
-		require.NoError(t, ch.Delete(), "delete channel")
+		require.NoError(t, ch.Delete(ctx), "delete channel")
 	}()
 
 	user := randomUser(t, c)
@@ -71,15 +78,15 @@ func TestChannel_DeleteReaction(t *testing.T) {
 		Text: "test message",
 		User: user,
 	}
-	msg, err := ch.SendMessage(msg, user.ID)
+	msg, err := ch.SendMessage(ctx, msg, user.ID)
 	require.NoError(t, err, "send message")
 
 	reaction := Reaction{Type: "love"}
 
-	msg, err = ch.SendReaction(&reaction, msg.ID, user.ID)
+	msg, err = ch.SendReaction(ctx, &reaction, msg.ID, user.ID)
 	require.NoError(t, err, "send reaction")
 
-	msg, err = ch.DeleteReaction(msg.ID, reaction.Type, user.ID)
+	msg, err = ch.DeleteReaction(ctx, msg.ID, reaction.Type, user.ID)
 	require.NoError(t, err, "delete reaction")
 
 	assert.Equal(t, 0, msg.ReactionCounts[reaction.Type], "reaction count")
@@ -87,10 +94,12 @@ func TestChannel_DeleteReaction(t *testing.T) {
 }
 
 func TestChannel_GetReactions(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 	ch := initChannel(t, c)
 	defer func() {
-		require.NoError(t, ch.Delete(), "delete channel")
+		require.NoError(t, ch.Delete(ctx), "delete channel")
 	}()
 
 	user := randomUser(t, c)
@@ -98,19 +107,19 @@ func TestChannel_GetReactions(t *testing.T) {
 		Text: "test message",
 		User: user,
 	}
-	msg, err := ch.SendMessage(msg, user.ID)
+	msg, err := ch.SendMessage(ctx, msg, user.ID)
 	require.NoError(t, err, "send message")
 
-	reactions, err := ch.GetReactions(msg.ID, nil)
+	reactions, err := ch.GetReactions(ctx, msg.ID, nil)
 	require.NoError(t, err, "get reactions")
 	assert.Empty(t, reactions, "reactions empty")
 
 	reaction := Reaction{Type: "love"}
 
-	msg, err = ch.SendReaction(&reaction, msg.ID, user.ID)
+	msg, err = ch.SendReaction(ctx, &reaction, msg.ID, user.ID)
 	require.NoError(t, err, "send reaction")
 
-	reactions, err = ch.GetReactions(msg.ID, nil)
+	reactions, err = ch.GetReactions(ctx, msg.ID, nil)
 	require.NoError(t, err, "get reactions")
 
 	assert.Condition(t, reactionExistsCondition(reactions, reaction.Type), "reaction exists")
@@ -0,0 +1,50 @@
+package stream_chat // nolint: golint
+
+import (
+	"context"
+	"net/http"
+)
+
+const requestIDHeader = "X-Request-Id"
+
+// ResponseMetadata holds the information the API returns with every response,
+// besides the response body.
+type ResponseMetadata struct {
+	// Endpoint is the API name of the called endpoint, for example SendMessage.
+	// It is empty for endpoints unknown to the client.
+	Endpoint   string
+	Method     string
+	Path       string
+	StatusCode int
+	// RequestID identifies the request on the server side; include it when reporting issues.
+	RequestID string
+	// RateLimit holds the quota of the endpoint after this request, if returned by the server.
+	RateLimit *RateLimitInfo
+}
+
+// ResponseCallback is called with the metadata of every response received by the client,
+// including error responses. Retried requests trigger the callback once per attempt.
+type ResponseCallback func(ctx context.Context, meta ResponseMetadata)
+
+// WithResponseCallback sets a callback called with the metadata of every API response.
+func WithResponseCallback(callback ResponseCallback) ClientOption {
+	return func(o *clientOptions) {
+		o.onResponse = callback
+	}
+}
+
+func newResponseMetadata(resp *http.Response) ResponseMetadata {
+	meta := ResponseMetadata{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get(requestIDHeader),
+		RateLimit:  NewRateLimitFromHeaders(resp.Header),
+	}
+
+	if r := resp.Request; r != nil {
+		meta.Endpoint = endpointFromContext(r.Context())
+		meta.Method = r.Method
+		meta.Path = r.URL.Path
+	}
+
+	return meta
+}
@@ -0,0 +1,52 @@
+package stream_chat // nolint: golint
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ResponseCallback(t *testing.T) {
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-1")
+		w.Header().Set("X-RateLimit-Limit", "60")
+		w.Header().Set("X-RateLimit-Remaining", "59")
+		w.Header().Set("X-RateLimit-Reset", "1609459200")
+
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":16,"message":"not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	var got []ResponseMetadata
+	c, err := NewClient("key", "secret", WithBaseURL(srv.URL), WithResponseCallback(func(_ context.Context, meta ResponseMetadata) {
+		got = append(got, meta)
+	}))
+	require.NoError(t, err)
+
+	_, err = c.GetAppConfig(ctx)
+	require.NoError(t, err)
+
+	err = c.DeleteCommand(ctx, "cmd")
+	require.True(t, IsNotFound(err))
+
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, "req-1", apiErr.RequestID)
+
+	rl := &RateLimitInfo{Limit: 60, Remaining: 59, Reset: 1609459200}
+	require.Equal(t, []ResponseMetadata{
+		{Endpoint: "GetApp", Method: http.MethodGet, Path: "/app", StatusCode: http.StatusOK, RequestID: "req-1", RateLimit: rl},
+		{Endpoint: "DeleteCommand", Method: http.MethodDelete, Path: "/commands/cmd", StatusCode: http.StatusNotFound, RequestID: "req-1", RateLimit: rl},
+	}, got)
+}
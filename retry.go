@@ -0,0 +1,223 @@
+package stream_chat // nolint: golint
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// RetryPolicy configures how failed requests are retried by the client.
+// Only idempotent requests (GET, HEAD, OPTIONS, PUT and DELETE) are retried, unless
+// RetryIdempotencyKeyed is set and the request carries an idempotency key.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	MaxAttempts int
+	// InitialBackoff is the base delay before the first retry. It doubles on every retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the computed delay between two attempts.
+	MaxBackoff time.Duration
+	// MaxRetryAfter is the longest delay requested by the server through the Retry-After or
+	// X-RateLimit-Reset headers that is honored. If the server asks to wait longer, the error
+	// is returned instead. Zero means any delay is honored.
+	MaxRetryAfter time.Duration
+	// RetryableStatusCodes lists the HTTP status codes which trigger a retry.
+	RetryableStatusCodes []int
+	// RetryIdempotencyKeyed allows retrying POST and PATCH requests which carry an
+	// idempotency key set with WithIdempotencyKey.
+	RetryIdempotencyKeyed bool
+}
+
+// DefaultRetryPolicy returns a RetryPolicy doing up to 3 attempts on rate limit errors,
+// server errors and network failures.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 250 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		MaxRetryAfter:  time.Minute,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+type idempotencyKeyCtxKey struct{}
+
+// WithIdempotencyKey returns a context which makes requests sent with it carry the given
+// idempotency key, so they can be retried safely if the RetryPolicy allows it.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtxKey{}, key)
+}
+
+func idempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyCtxKey{}).(string)
+	return key
+}
+
+// canRetry reports whether the request may be sent more than once.
+func (p *RetryPolicy) canRetry(r *http.Request) bool {
+	return p.MaxAttempts > 1 && isIdempotent(r, p.RetryIdempotencyKeyed)
+}
+
+// isIdempotent reports whether the request can be sent more than once: its body can be sent
+// again and its method is idempotent, or keyed is true and it carries an idempotency key.
+func isIdempotent(r *http.Request, keyed bool) bool {
+	if r.Body != nil && r.GetBody == nil {
+		return false
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPost, http.MethodPatch:
+		return keyed && r.Header.Get(idempotencyKeyHeader) != ""
+	default:
+		return false
+	}
+}
+
+// backoff returns the delay before the given retry, attempt starting at 1.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.InitialBackoff << uint(attempt-1)
+	if d <= 0 || (p.MaxBackoff > 0 && d > p.MaxBackoff) {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	// full jitter, see https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/
+	return time.Duration(rand.Int63n(int64(d)) + 1)
+}
+
+// retryDelay decides whether the outcome of an attempt should be retried and how long to wait.
+func (p *RetryPolicy) retryDelay(ctx context.Context, attempt int, resp *http.Response, err error) (time.Duration, bool) {
+	if err != nil {
+		// the caller gave up, the client side quota is exhausted or the circuit
+		// breaker is open, there is no point in trying again
+		return 0, ctx.Err() == nil && !errors.Is(err, ErrRateLimitExceeded) && !errors.Is(err, ErrCircuitOpen)
+	}
+
+	retryable := false
+	for _, code := range p.RetryableStatusCodes {
+		if resp.StatusCode == code {
+			retryable = true
+			break
+		}
+	}
+	if !retryable {
+		return 0, false
+	}
+
+	wait, ok := retryAfter(resp)
+	if !ok {
+		return p.backoff(attempt), true
+	}
+	if p.MaxRetryAfter > 0 && wait > p.MaxRetryAfter {
+		return 0, false
+	}
+	return wait, true
+}
+
+// retryAfter returns the delay requested by the server, either through the Retry-After header
+// or, for rate limited requests, the reset time of the rate limit window.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second, true
+		}
+		if at, err := http.ParseTime(v); err == nil {
+			return nonNegative(time.Until(at)), true
+		}
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if rl := NewRateLimitFromHeaders(resp.Header); rl != nil {
+			return nonNegative(time.Until(rl.ResetTime())), true
+		}
+	}
+
+	return 0, false
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// do sends the request, retrying it according to the client RetryPolicy.
+func (c *Client) do(r *http.Request) (resp *http.Response, err error) {
+	if c.metrics != nil {
+		defer func(start time.Time) {
+			c.observeRequest(r, start, resp)
+		}(time.Now())
+	}
+
+	policy := c.retryPolicy
+	if policy == nil || !policy.canRetry(r) {
+		return c.send(r)
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err = c.send(r)
+		if attempt >= policy.MaxAttempts {
+			return resp, err
+		}
+
+		wait, retry := policy.retryDelay(r.Context(), attempt, resp, err)
+		if !retry {
+			return resp, err
+		}
+		if c.metrics != nil {
+			c.metrics.ObserveRetry(endpointFromContext(r.Context()), r.Method)
+		}
+
+		if resp != nil {
+			c.logf("chat-client: retrying %s %s in %s after status %d (attempt %d/%d)",
+				r.Method, r.URL.Path, wait, resp.StatusCode, attempt+1, policy.MaxAttempts)
+
+			// drain the body so that the connection can be reused
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			_ = resp.Body.Close()
+		} else {
+			c.logf("chat-client: retrying %s %s in %s after error: %v (attempt %d/%d)",
+				r.Method, r.URL.Path, wait, err, attempt+1, policy.MaxAttempts)
+		}
+
+		if err := sleepContext(r.Context(), wait); err != nil {
+			return nil, err
+		}
+
+		if r.GetBody != nil {
+			body, err := r.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r.Body = body
+		}
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,157 @@
+package stream_chat // nolint: golint
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newRetryTestClient returns a client of a server calling handler, retrying with short backoffs
+// unless the options set another policy.
+func newRetryTestClient(t *testing.T, handler http.HandlerFunc, options ...ClientOption) *Client {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	options = append([]ClientOption{WithBaseURL(srv.URL), WithRetryPolicy(retryTestPolicy())}, options...)
+	c, err := NewClient("key", "secret", options...)
+	require.NoError(t, err)
+	return c
+}
+
+func retryTestPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = 5 * time.Millisecond
+	return policy
+}
+
+func TestClient_Retry(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("retries idempotent request on server error", func(t *testing.T) {
+		var calls int32
+		c := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte(`{"app":{"name":"test"}}`))
+		})
+
+		app, err := c.GetAppConfig(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "test", app.Name)
+		require.EqualValues(t, 3, atomic.LoadInt32(&calls))
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		var calls int32
+		c := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusBadGateway)
+		})
+
+		_, err := c.GetAppConfig(ctx)
+		require.Error(t, err)
+		require.EqualValues(t, 3, atomic.LoadInt32(&calls))
+	})
+
+	t.Run("does not retry non retryable status", func(t *testing.T) {
+		var calls int32
+		c := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusBadRequest)
+		})
+
+		_, err := c.GetAppConfig(ctx)
+		require.Error(t, err)
+		require.EqualValues(t, 1, atomic.LoadInt32(&calls))
+	})
+
+	t.Run("does not retry POST without idempotency key", func(t *testing.T) {
+		var calls int32
+		c := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+
+		_, err := c.UpsertUser(ctx, &User{ID: "user"})
+		require.Error(t, err)
+		require.EqualValues(t, 1, atomic.LoadInt32(&calls))
+	})
+
+	t.Run("retries POST with idempotency key and rewinds body", func(t *testing.T) {
+		var calls int32
+		policy := retryTestPolicy()
+		policy.RetryIdempotencyKeyed = true
+		c := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"users":{"user":{"id":"user"}}}`, string(body))
+			require.Equal(t, "key-1", r.Header.Get(idempotencyKeyHeader))
+
+			if atomic.AddInt32(&calls, 1) < 2 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			_, _ = w.Write([]byte(`{"users":{"user":{"id":"user"}}}`))
+		}, WithRetryPolicy(policy))
+
+		u, err := c.UpsertUser(WithIdempotencyKey(ctx, "key-1"), &User{ID: "user"})
+		require.NoError(t, err)
+		require.Equal(t, "user", u.ID)
+		require.EqualValues(t, 2, atomic.LoadInt32(&calls))
+	})
+
+	t.Run("honors Retry-After", func(t *testing.T) {
+		var calls int32
+		c := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) < 2 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			_, _ = w.Write([]byte(`{}`))
+		})
+
+		start := time.Now()
+		_, err := c.GetAppConfig(ctx)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, int64(time.Since(start)), int64(time.Second))
+	})
+
+	t.Run("gives up when server asks to wait too long", func(t *testing.T) {
+		var calls int32
+		c := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.Header().Set("X-RateLimit-Limit", "60")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+			w.WriteHeader(http.StatusTooManyRequests)
+		})
+
+		_, err := c.GetAppConfig(ctx)
+		require.True(t, IsRateLimited(err))
+		require.EqualValues(t, 1, atomic.LoadInt32(&calls))
+	})
+
+	t.Run("stops when context is canceled", func(t *testing.T) {
+		c := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "10")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+
+		ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+
+		_, err := c.GetAppConfig(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
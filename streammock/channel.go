@@ -0,0 +1,250 @@
+package streammock
+
+import (
+	"context"
+	"time"
+
+	stream "github.com/GetStream/stream-chat-go/v4"
+)
+
+// Channel is a mock of stream_chat.ChannelAPI, returned by Client.Channel.
+type Channel struct {
+	Mock
+
+	Type string
+	ID   string
+}
+
+var _ stream.ChannelAPI = (*Channel)(nil)
+
+// Data implements stream.ChannelAPI. Without programmed results, it returns a channel
+// with the type and ID of the mock.
+func (ch *Channel) Data() *stream.Channel {
+	r := ch.called("Data")
+	v := &stream.Channel{Type: ch.Type, ID: ch.ID, CID: ch.Type + ":" + ch.ID}
+	r.value(0, &v)
+	return v
+}
+
+// Query implements stream.ChannelAPI.
+func (ch *Channel) Query(ctx context.Context, data map[string]interface{}) error {
+	return ch.called("Query", data).err(0)
+}
+
+// Update implements stream.ChannelAPI.
+func (ch *Channel) Update(ctx context.Context, options map[string]interface{}, message *stream.Message) error {
+	return ch.called("Update", options, message).err(0)
+}
+
+// PartialUpdate implements stream.ChannelAPI.
+func (ch *Channel) PartialUpdate(ctx context.Context, update stream.PartialUpdate) error {
+	return ch.called("PartialUpdate", update).err(0)
+}
+
+// Delete implements stream.ChannelAPI.
+func (ch *Channel) Delete(ctx context.Context) error {
+	return ch.called("Delete").err(0)
+}
+
+// Truncate implements stream.ChannelAPI.
+func (ch *Channel) Truncate(ctx context.Context) error {
+	return ch.called("Truncate").err(0)
+}
+
+// Show implements stream.ChannelAPI.
+func (ch *Channel) Show(ctx context.Context, userID string) error {
+	return ch.called("Show", userID).err(0)
+}
+
+// Hide implements stream.ChannelAPI.
+func (ch *Channel) Hide(ctx context.Context, userID string) error {
+	return ch.called("Hide", userID).err(0)
+}
+
+// HideWithHistoryClear implements stream.ChannelAPI.
+func (ch *Channel) HideWithHistoryClear(ctx context.Context, userID string) error {
+	return ch.called("HideWithHistoryClear", userID).err(0)
+}
+
+// Mute implements stream.ChannelAPI.
+func (ch *Channel) Mute(ctx context.Context, userID string, expiration *time.Duration) (*stream.ChannelMuteResponse, error) {
+	r := ch.called("Mute", userID, expiration)
+	var v *stream.ChannelMuteResponse
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// Unmute implements stream.ChannelAPI.
+func (ch *Channel) Unmute(ctx context.Context, userID string) error {
+	return ch.called("Unmute", userID).err(0)
+}
+
+// MarkRead implements stream.ChannelAPI.
+func (ch *Channel) MarkRead(ctx context.Context, userID string, options map[string]interface{}) error {
+	return ch.called("MarkRead", userID, options).err(0)
+}
+
+// AddMembers implements stream.ChannelAPI.
+func (ch *Channel) AddMembers(ctx context.Context, userIDs []string, message *stream.Message, options map[string]interface{}) error {
+	return ch.called("AddMembers", userIDs, message, options).err(0)
+}
+
+// RemoveMembers implements stream.ChannelAPI.
+func (ch *Channel) RemoveMembers(ctx context.Context, userIDs []string, message *stream.Message) error {
+	return ch.called("RemoveMembers", userIDs, message).err(0)
+}
+
+// QueryMembers implements stream.ChannelAPI.
+func (ch *Channel) QueryMembers(ctx context.Context, q *stream.QueryOption, sorters ...*stream.SortOption) ([]*stream.ChannelMember, error) {
+	r := ch.called("QueryMembers", q, sorters)
+	var v []*stream.ChannelMember
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// AddModerators implements stream.ChannelAPI.
+func (ch *Channel) AddModerators(ctx context.Context, userIDs ...string) error {
+	return ch.called("AddModerators", userIDs).err(0)
+}
+
+// AddModeratorsWithMessage implements stream.ChannelAPI.
+func (ch *Channel) AddModeratorsWithMessage(ctx context.Context, userIDs []string, msg *stream.Message) error {
+	return ch.called("AddModeratorsWithMessage", userIDs, msg).err(0)
+}
+
+// DemoteModerators implements stream.ChannelAPI.
+func (ch *Channel) DemoteModerators(ctx context.Context, userIDs ...string) error {
+	return ch.called("DemoteModerators", userIDs).err(0)
+}
+
+// DemoteModeratorsWithMessage implements stream.ChannelAPI.
+func (ch *Channel) DemoteModeratorsWithMessage(ctx context.Context, userIDs []string, msg *stream.Message) error {
+	return ch.called("DemoteModeratorsWithMessage", userIDs, msg).err(0)
+}
+
+// InviteMembers implements stream.ChannelAPI.
+func (ch *Channel) InviteMembers(ctx context.Context, userIDs ...string) error {
+	return ch.called("InviteMembers", userIDs).err(0)
+}
+
+// InviteMembersWithMessage implements stream.ChannelAPI.
+func (ch *Channel) InviteMembersWithMessage(ctx context.Context, userIDs []string, msg *stream.Message) error {
+	return ch.called("InviteMembersWithMessage", userIDs, msg).err(0)
+}
+
+// AcceptInvite implements stream.ChannelAPI.
+func (ch *Channel) AcceptInvite(ctx context.Context, userID string, message *stream.Message) error {
+	return ch.called("AcceptInvite", userID, message).err(0)
+}
+
+// RejectInvite implements stream.ChannelAPI.
+func (ch *Channel) RejectInvite(ctx context.Context, userID string, message *stream.Message) error {
+	return ch.called("RejectInvite", userID, message).err(0)
+}
+
+// BanUser implements stream.ChannelAPI.
+func (ch *Channel) BanUser(ctx context.Context, targetID, userID string, options map[string]interface{}) error {
+	return ch.called("BanUser", targetID, userID, options).err(0)
+}
+
+// UnBanUser implements stream.ChannelAPI.
+func (ch *Channel) UnBanUser(ctx context.Context, targetID string, options map[string]string) error {
+	return ch.called("UnBanUser", targetID, options).err(0)
+}
+
+// ShadowBan implements stream.ChannelAPI.
+func (ch *Channel) ShadowBan(ctx context.Context, userID, bannedByID string, options map[string]interface{}) error {
+	return ch.called("ShadowBan", userID, bannedByID, options).err(0)
+}
+
+// RemoveShadowBan implements stream.ChannelAPI.
+func (ch *Channel) RemoveShadowBan(ctx context.Context, userID string) error {
+	return ch.called("RemoveShadowBan", userID).err(0)
+}
+
+// SendMessage implements stream.ChannelAPI.
+func (ch *Channel) SendMessage(ctx context.Context, message *stream.Message, userID string, options ...stream.SendMessageOption) (*stream.Message, error) {
+	r := ch.called("SendMessage", message, userID, options)
+	var v *stream.Message
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// ImportMessages implements stream.ChannelAPI.
+func (ch *Channel) ImportMessages(ctx context.Context, messages ...*stream.Message) (*stream.ImportChannelMessagesResponse, error) {
+	r := ch.called("ImportMessages", messages)
+	var v *stream.ImportChannelMessagesResponse
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// GetReplies implements stream.ChannelAPI.
+func (ch *Channel) GetReplies(ctx context.Context, parentID string, options map[string][]string) ([]*stream.Message, error) {
+	r := ch.called("GetReplies", parentID, options)
+	var v []*stream.Message
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// SendAction implements stream.ChannelAPI.
+func (ch *Channel) SendAction(ctx context.Context, msgID string, formData map[string]string) (*stream.Message, error) {
+	r := ch.called("SendAction", msgID, formData)
+	var v *stream.Message
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// SendReaction implements stream.ChannelAPI.
+func (ch *Channel) SendReaction(ctx context.Context, reaction *stream.Reaction, messageID, userID string) (*stream.Message, error) {
+	r := ch.called("SendReaction", reaction, messageID, userID)
+	var v *stream.Message
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// DeleteReaction implements stream.ChannelAPI.
+func (ch *Channel) DeleteReaction(ctx context.Context, messageID, reactionType, userID string) (*stream.Message, error) {
+	r := ch.called("DeleteReaction", messageID, reactionType, userID)
+	var v *stream.Message
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// GetReactions implements stream.ChannelAPI.
+func (ch *Channel) GetReactions(ctx context.Context, messageID string, options map[string][]string) ([]*stream.Reaction, error) {
+	r := ch.called("GetReactions", messageID, options)
+	var v []*stream.Reaction
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// SendEvent implements stream.ChannelAPI.
+func (ch *Channel) SendEvent(ctx context.Context, event *stream.Event, userID string) error {
+	return ch.called("SendEvent", event, userID).err(0)
+}
+
+// SendFile implements stream.ChannelAPI.
+func (ch *Channel) SendFile(ctx context.Context, request stream.SendFileRequest) (string, error) {
+	r := ch.called("SendFile", request)
+	var v string
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// SendImage implements stream.ChannelAPI.
+func (ch *Channel) SendImage(ctx context.Context, request stream.SendFileRequest) (string, error) {
+	r := ch.called("SendImage", request)
+	var v string
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// DeleteFile implements stream.ChannelAPI.
+func (ch *Channel) DeleteFile(ctx context.Context, location string) error {
+	return ch.called("DeleteFile", location).err(0)
+}
+
+// DeleteImage implements stream.ChannelAPI.
+func (ch *Channel) DeleteImage(ctx context.Context, location string) error {
+	return ch.called("DeleteImage", location).err(0)
+}
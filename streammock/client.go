@@ -0,0 +1,529 @@
+package streammock
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	stream "github.com/GetStream/stream-chat-go/v4"
+)
+
+// Client is a mock of stream_chat.ChatClient.
+type Client struct {
+	Mock
+
+	channelsMu sync.Mutex
+	channels   map[string]*Channel
+}
+
+// NewClient creates a Client without programmed results.
+func NewClient() *Client {
+	return &Client{channels: make(map[string]*Channel)}
+}
+
+var _ stream.ChatClient = (*Client)(nil)
+
+// Channel returns the mock of the channel with the given type and ID, to program its results
+// and inspect its calls. The same mock is returned for the same channel.
+func (c *Client) Channel(channelType, channelID string) *Channel {
+	c.channelsMu.Lock()
+	defer c.channelsMu.Unlock()
+
+	cid := channelType + ":" + channelID
+	ch, ok := c.channels[cid]
+	if !ok {
+		ch = &Channel{Type: channelType, ID: channelID}
+		c.channels[cid] = ch
+	}
+	return ch
+}
+
+// ChannelAPI implements stream.ChatClient, returning the mock of the channel.
+func (c *Client) ChannelAPI(channelType, channelID string) stream.ChannelAPI {
+	return c.Channel(channelType, channelID)
+}
+
+// UpsertUser implements stream.UserAPI.
+func (c *Client) UpsertUser(ctx context.Context, user *stream.User) (*stream.User, error) {
+	r := c.called("UpsertUser", user)
+	var v *stream.User
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// UpdateUser implements stream.UserAPI.
+func (c *Client) UpdateUser(ctx context.Context, user *stream.User) (*stream.User, error) {
+	r := c.called("UpdateUser", user)
+	var v *stream.User
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// UpsertUsers implements stream.UserAPI.
+func (c *Client) UpsertUsers(ctx context.Context, users ...*stream.User) (map[string]*stream.User, error) {
+	r := c.called("UpsertUsers", users)
+	var v map[string]*stream.User
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// UpdateUsers implements stream.UserAPI.
+func (c *Client) UpdateUsers(ctx context.Context, users ...*stream.User) (map[string]*stream.User, error) {
+	r := c.called("UpdateUsers", users)
+	var v map[string]*stream.User
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// PartialUpdateUser implements stream.UserAPI.
+func (c *Client) PartialUpdateUser(ctx context.Context, update stream.PartialUserUpdate) (*stream.User, error) {
+	r := c.called("PartialUpdateUser", update)
+	var v *stream.User
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// PartialUpdateUsers implements stream.UserAPI.
+func (c *Client) PartialUpdateUsers(ctx context.Context, updates []stream.PartialUserUpdate) (map[string]*stream.User, error) {
+	r := c.called("PartialUpdateUsers", updates)
+	var v map[string]*stream.User
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// BulkUpsertUsers implements stream.UserAPI.
+func (c *Client) BulkUpsertUsers(ctx context.Context, users []*stream.User, options ...stream.BulkOption) (map[string]*stream.User, error) {
+	r := c.called("BulkUpsertUsers", users, options)
+	var v map[string]*stream.User
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// BulkPartialUpdateUsers implements stream.UserAPI.
+func (c *Client) BulkPartialUpdateUsers(ctx context.Context, updates []stream.PartialUserUpdate, options ...stream.BulkOption) (map[string]*stream.User, error) {
+	r := c.called("BulkPartialUpdateUsers", updates, options)
+	var v map[string]*stream.User
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// QueryUsers implements stream.UserAPI.
+func (c *Client) QueryUsers(ctx context.Context, q *stream.QueryOption, sorters ...*stream.SortOption) ([]*stream.User, error) {
+	r := c.called("QueryUsers", q, sorters)
+	var v []*stream.User
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// ExportUser implements stream.UserAPI.
+func (c *Client) ExportUser(ctx context.Context, targetID string, options map[string][]string) (*stream.User, error) {
+	r := c.called("ExportUser", targetID, options)
+	var v *stream.User
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// DeactivateUser implements stream.UserAPI.
+func (c *Client) DeactivateUser(ctx context.Context, targetID string, options map[string]interface{}) error {
+	return c.called("DeactivateUser", targetID, options).err(0)
+}
+
+// ReactivateUser implements stream.UserAPI.
+func (c *Client) ReactivateUser(ctx context.Context, targetID string, options map[string]interface{}) error {
+	return c.called("ReactivateUser", targetID, options).err(0)
+}
+
+// DeleteUser implements stream.UserAPI.
+func (c *Client) DeleteUser(ctx context.Context, targetID string, options map[string][]string) error {
+	return c.called("DeleteUser", targetID, options).err(0)
+}
+
+// DeleteUsers implements stream.UserAPI.
+func (c *Client) DeleteUsers(ctx context.Context, userIDs []string, options stream.DeleteUserOptions) (string, error) {
+	r := c.called("DeleteUsers", userIDs, options)
+	var v string
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// BulkDeleteUsers implements stream.UserAPI.
+func (c *Client) BulkDeleteUsers(ctx context.Context, userIDs []string, deleteOptions stream.DeleteUserOptions, options ...stream.BulkOption) ([]string, error) {
+	r := c.called("BulkDeleteUsers", userIDs, deleteOptions, options)
+	var v []string
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// SendUserCustomEvent implements stream.UserAPI.
+func (c *Client) SendUserCustomEvent(ctx context.Context, targetUserID string, event *stream.UserCustomEvent) error {
+	return c.called("SendUserCustomEvent", targetUserID, event).err(0)
+}
+
+// GetDevices implements stream.UserAPI.
+func (c *Client) GetDevices(ctx context.Context, userID string) ([]*stream.Device, error) {
+	r := c.called("GetDevices", userID)
+	var v []*stream.Device
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// AddDevice implements stream.UserAPI.
+func (c *Client) AddDevice(ctx context.Context, device *stream.Device) error {
+	return c.called("AddDevice", device).err(0)
+}
+
+// DeleteDevice implements stream.UserAPI.
+func (c *Client) DeleteDevice(ctx context.Context, userID, deviceID string) error {
+	return c.called("DeleteDevice", userID, deviceID).err(0)
+}
+
+// CreateToken implements stream.UserAPI.
+func (c *Client) CreateToken(userID string, expire time.Time, issuedAt ...time.Time) (string, error) {
+	r := c.called("CreateToken", userID, expire, issuedAt)
+	var v string
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// CreateTokenWithClaims implements stream.UserAPI.
+func (c *Client) CreateTokenWithClaims(userID string, claims map[string]interface{}) (string, error) {
+	r := c.called("CreateTokenWithClaims", userID, claims)
+	var v string
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// ParseUserToken implements stream.UserAPI.
+func (c *Client) ParseUserToken(token string) (map[string]interface{}, error) {
+	r := c.called("ParseUserToken", token)
+	var v map[string]interface{}
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// VerifyUserToken implements stream.UserAPI.
+func (c *Client) VerifyUserToken(token string) (string, error) {
+	r := c.called("VerifyUserToken", token)
+	var v string
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// RevokeUserToken implements stream.UserAPI.
+func (c *Client) RevokeUserToken(ctx context.Context, userID string, before *time.Time) error {
+	return c.called("RevokeUserToken", userID, before).err(0)
+}
+
+// RevokeUsersTokens implements stream.UserAPI.
+func (c *Client) RevokeUsersTokens(ctx context.Context, userIDs []string, before *time.Time) error {
+	return c.called("RevokeUsersTokens", userIDs, before).err(0)
+}
+
+// MuteUser implements stream.ModerationAPI.
+func (c *Client) MuteUser(ctx context.Context, targetID, userID string, options map[string]interface{}) error {
+	return c.called("MuteUser", targetID, userID, options).err(0)
+}
+
+// MuteUsers implements stream.ModerationAPI.
+func (c *Client) MuteUsers(ctx context.Context, targetIDs []string, userID string, options map[string]interface{}) error {
+	return c.called("MuteUsers", targetIDs, userID, options).err(0)
+}
+
+// UnmuteUser implements stream.ModerationAPI.
+func (c *Client) UnmuteUser(ctx context.Context, targetID, userID string) error {
+	return c.called("UnmuteUser", targetID, userID).err(0)
+}
+
+// UnmuteUsers implements stream.ModerationAPI.
+func (c *Client) UnmuteUsers(ctx context.Context, targetIDs []string, userID string) error {
+	return c.called("UnmuteUsers", targetIDs, userID).err(0)
+}
+
+// FlagUser implements stream.ModerationAPI.
+func (c *Client) FlagUser(ctx context.Context, targetID string, options map[string]interface{}) error {
+	return c.called("FlagUser", targetID, options).err(0)
+}
+
+// UnFlagUser implements stream.ModerationAPI.
+func (c *Client) UnFlagUser(ctx context.Context, targetID string, options map[string]interface{}) error {
+	return c.called("UnFlagUser", targetID, options).err(0)
+}
+
+// FlagMessage implements stream.ModerationAPI.
+func (c *Client) FlagMessage(ctx context.Context, msgID, userID string) error {
+	return c.called("FlagMessage", msgID, userID).err(0)
+}
+
+// UnflagMessage implements stream.ModerationAPI.
+func (c *Client) UnflagMessage(ctx context.Context, msgID, userID string) error {
+	return c.called("UnflagMessage", msgID, userID).err(0)
+}
+
+// QueryMessageFlags implements stream.ModerationAPI.
+func (c *Client) QueryMessageFlags(ctx context.Context, q *stream.QueryOption) ([]*stream.MessageFlag, error) {
+	r := c.called("QueryMessageFlags", q)
+	var v []*stream.MessageFlag
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// BanUser implements stream.ModerationAPI.
+func (c *Client) BanUser(ctx context.Context, targetID, userID string, options map[string]interface{}) error {
+	return c.called("BanUser", targetID, userID, options).err(0)
+}
+
+// UnBanUser implements stream.ModerationAPI.
+func (c *Client) UnBanUser(ctx context.Context, targetID string, options map[string]string) error {
+	return c.called("UnBanUser", targetID, options).err(0)
+}
+
+// ShadowBan implements stream.ModerationAPI.
+func (c *Client) ShadowBan(ctx context.Context, userID, bannedByID string, options map[string]interface{}) error {
+	return c.called("ShadowBan", userID, bannedByID, options).err(0)
+}
+
+// RemoveShadowBan implements stream.ModerationAPI.
+func (c *Client) RemoveShadowBan(ctx context.Context, userID string, options map[string]string) error {
+	return c.called("RemoveShadowBan", userID, options).err(0)
+}
+
+// GetMessage implements stream.MessageAPI.
+func (c *Client) GetMessage(ctx context.Context, msgID string) (*stream.Message, error) {
+	r := c.called("GetMessage", msgID)
+	var v *stream.Message
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// UpdateMessage implements stream.MessageAPI.
+func (c *Client) UpdateMessage(ctx context.Context, msg *stream.Message, msgID string) (*stream.Message, error) {
+	r := c.called("UpdateMessage", msg, msgID)
+	var v *stream.Message
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// PartialUpdateMessage implements stream.MessageAPI.
+func (c *Client) PartialUpdateMessage(ctx context.Context, messageID string, updates stream.PartialUpdate, options map[string]interface{}) (*stream.Message, error) {
+	r := c.called("PartialUpdateMessage", messageID, updates, options)
+	var v *stream.Message
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// PinMessage implements stream.MessageAPI.
+func (c *Client) PinMessage(ctx context.Context, msgID, pinnedByID string, expiration *time.Time) (*stream.Message, error) {
+	r := c.called("PinMessage", msgID, pinnedByID, expiration)
+	var v *stream.Message
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// UnPinMessage implements stream.MessageAPI.
+func (c *Client) UnPinMessage(ctx context.Context, msgID, userID string) (*stream.Message, error) {
+	r := c.called("UnPinMessage", msgID, userID)
+	var v *stream.Message
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// DeleteMessage implements stream.MessageAPI.
+func (c *Client) DeleteMessage(ctx context.Context, msgID string) error {
+	return c.called("DeleteMessage", msgID).err(0)
+}
+
+// HardDeleteMessage implements stream.MessageAPI.
+func (c *Client) HardDeleteMessage(ctx context.Context, msgID string) error {
+	return c.called("HardDeleteMessage", msgID).err(0)
+}
+
+// MarkAllRead implements stream.MessageAPI.
+func (c *Client) MarkAllRead(ctx context.Context, userID string) error {
+	return c.called("MarkAllRead", userID).err(0)
+}
+
+// Search implements stream.MessageAPI.
+func (c *Client) Search(ctx context.Context, request stream.SearchRequest) ([]*stream.Message, error) {
+	r := c.called("Search", request)
+	var v []*stream.Message
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// SearchWithFullResponse implements stream.MessageAPI.
+func (c *Client) SearchWithFullResponse(ctx context.Context, request stream.SearchRequest) (*stream.SearchResponse, error) {
+	r := c.called("SearchWithFullResponse", request)
+	var v *stream.SearchResponse
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// CreateChannelAPI implements stream.ChatClient. Without programmed results, it returns
+// the mock of the channel given by Channel.
+func (c *Client) CreateChannelAPI(ctx context.Context, chanType, chanID, userID string, data map[string]interface{}) (stream.ChannelAPI, error) {
+	r := c.called("CreateChannelAPI", chanType, chanID, userID, data)
+	var v stream.ChannelAPI = c.Channel(chanType, chanID)
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// QueryChannelsAPI implements stream.ChatClient. Program it with a []stream_chat.ChannelAPI
+// holding mocks given by Channel.
+func (c *Client) QueryChannelsAPI(ctx context.Context, q *stream.QueryOption, sort ...*stream.SortOption) ([]stream.ChannelAPI, error) {
+	r := c.called("QueryChannelsAPI", q, sort)
+	var v []stream.ChannelAPI
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// DeleteChannels implements stream.ChatClient.
+func (c *Client) DeleteChannels(ctx context.Context, cids []string, hardDelete bool) (string, error) {
+	r := c.called("DeleteChannels", cids, hardDelete)
+	var v string
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// BulkDeleteChannels implements stream.ChatClient.
+func (c *Client) BulkDeleteChannels(ctx context.Context, cids []string, hardDelete bool, options ...stream.BulkOption) ([]string, error) {
+	r := c.called("BulkDeleteChannels", cids, hardDelete, options)
+	var v []string
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// ExportChannels implements stream.ChatClient.
+func (c *Client) ExportChannels(ctx context.Context, channels []*stream.ExportableChannel, clearDeletedMessageText, includeTruncatedMessages *bool) (string, error) {
+	r := c.called("ExportChannels", channels, clearDeletedMessageText, includeTruncatedMessages)
+	var v string
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// GetExportChannelsTask implements stream.ChatClient.
+func (c *Client) GetExportChannelsTask(ctx context.Context, taskID string) (*stream.Task, error) {
+	r := c.called("GetExportChannelsTask", taskID)
+	var v *stream.Task
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// GetTask implements stream.ChatClient.
+func (c *Client) GetTask(ctx context.Context, id string) (*stream.Task, error) {
+	r := c.called("GetTask", id)
+	var v *stream.Task
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// WaitForTask implements stream.ChatClient.
+func (c *Client) WaitForTask(ctx context.Context, taskID string, options ...stream.WaitOption) (*stream.Task, error) {
+	r := c.called("WaitForTask", taskID, options)
+	var v *stream.Task
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// CreateChannelType implements stream.ChatClient.
+func (c *Client) CreateChannelType(ctx context.Context, chType *stream.ChannelType) (*stream.ChannelType, error) {
+	r := c.called("CreateChannelType", chType)
+	var v *stream.ChannelType
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// GetChannelType implements stream.ChatClient.
+func (c *Client) GetChannelType(ctx context.Context, chanType string) (*stream.ChannelType, error) {
+	r := c.called("GetChannelType", chanType)
+	var v *stream.ChannelType
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// ListChannelTypes implements stream.ChatClient.
+func (c *Client) ListChannelTypes(ctx context.Context) (map[string]*stream.ChannelType, error) {
+	r := c.called("ListChannelTypes")
+	var v map[string]*stream.ChannelType
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// UpdateChannelType implements stream.ChatClient.
+func (c *Client) UpdateChannelType(ctx context.Context, name string, options map[string]interface{}) error {
+	return c.called("UpdateChannelType", name, options).err(0)
+}
+
+// DeleteChannelType implements stream.ChatClient.
+func (c *Client) DeleteChannelType(ctx context.Context, name string) error {
+	return c.called("DeleteChannelType", name).err(0)
+}
+
+// CreateCommand implements stream.ChatClient.
+func (c *Client) CreateCommand(ctx context.Context, cmd *stream.Command) (*stream.Command, error) {
+	r := c.called("CreateCommand", cmd)
+	var v *stream.Command
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// GetCommand implements stream.ChatClient.
+func (c *Client) GetCommand(ctx context.Context, cmdName string) (*stream.Command, error) {
+	r := c.called("GetCommand", cmdName)
+	var v *stream.Command
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// ListCommands implements stream.ChatClient.
+func (c *Client) ListCommands(ctx context.Context) ([]*stream.Command, error) {
+	r := c.called("ListCommands")
+	var v []*stream.Command
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// UpdateCommand implements stream.ChatClient.
+func (c *Client) UpdateCommand(ctx context.Context, cmdName string, options map[string]interface{}) (*stream.Command, error) {
+	r := c.called("UpdateCommand", cmdName, options)
+	var v *stream.Command
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// DeleteCommand implements stream.ChatClient.
+func (c *Client) DeleteCommand(ctx context.Context, cmdName string) error {
+	return c.called("DeleteCommand", cmdName).err(0)
+}
+
+// GetAppConfig implements stream.ChatClient.
+func (c *Client) GetAppConfig(ctx context.Context) (*stream.AppConfig, error) {
+	r := c.called("GetAppConfig")
+	var v *stream.AppConfig
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// UpdateAppSettings implements stream.ChatClient.
+func (c *Client) UpdateAppSettings(ctx context.Context, settings *stream.AppSettings) error {
+	return c.called("UpdateAppSettings", settings).err(0)
+}
+
+// RevokeTokens implements stream.ChatClient.
+func (c *Client) RevokeTokens(ctx context.Context, before *time.Time) error {
+	return c.called("RevokeTokens", before).err(0)
+}
+
+// GetRateLimits implements stream.ChatClient.
+func (c *Client) GetRateLimits(ctx context.Context, options ...stream.GetRateLimitsOption) (stream.GetRateLimitsResponse, error) {
+	r := c.called("GetRateLimits", options)
+	var v stream.GetRateLimitsResponse
+	r.value(0, &v)
+	return v, r.err(1)
+}
+
+// VerifyWebhook implements stream.ChatClient.
+func (c *Client) VerifyWebhook(body, signature []byte) bool {
+	var v bool
+	c.called("VerifyWebhook", body, signature).value(0, &v)
+	return v
+}
@@ -0,0 +1,149 @@
+// Package streammock provides implementations of the stream_chat.ChatClient and
+// stream_chat.ChannelAPI interfaces recording the calls and returning programmed results,
+// to unit test code using the client:
+//
+//	client := streammock.NewClient()
+//	client.On("GetMessage", &stream_chat.Message{ID: "msg", Text: "hello"}, nil)
+//	client.Channel("messaging", "general").On("SendMessage", nil, errors.New("unavailable"))
+//
+//	err := codeUnderTest(client)
+//
+//	calls := client.CallsTo("GetMessage")
+//
+// Methods without programmed results return zero values and a nil error.
+package streammock
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Call is a recorded call of a mock method.
+type Call struct {
+	// Method is the name of the method, like "SendMessage".
+	Method string
+	// Args are the arguments of the call, without the context.
+	// Variadic arguments are given as a slice.
+	Args []interface{}
+}
+
+// ResultsFunc computes the results of a call from its arguments.
+type ResultsFunc func(args ...interface{}) []interface{}
+
+// Mock records the calls and holds the programmed results of Client and Channel.
+type Mock struct {
+	mu      sync.Mutex
+	calls   []Call
+	results map[string][]ResultsFunc
+}
+
+// On programs the results of the next call of the method, in the order of its results:
+// for GetMessage, a *stream_chat.Message and an error. Nil is the zero value of any type.
+//
+// Successive calls of On program successive calls of the method. The last results are
+// returned by all the calls after.
+func (m *Mock) On(method string, results ...interface{}) {
+	m.OnFunc(method, func(...interface{}) []interface{} { return results })
+}
+
+// OnFunc programs the next call of the method to return the results computed by fn.
+func (m *Mock) OnFunc(method string, fn ResultsFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.results == nil {
+		m.results = make(map[string][]ResultsFunc)
+	}
+	m.results[method] = append(m.results[method], fn)
+}
+
+// Calls returns the recorded calls, in order.
+func (m *Mock) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Call(nil), m.calls...)
+}
+
+// CallsTo returns the recorded calls of the method, in order.
+func (m *Mock) CallsTo(method string) []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var calls []Call
+	for _, c := range m.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Reset forgets the recorded calls and the programmed results.
+func (m *Mock) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = nil
+	m.results = nil
+}
+
+// called records the call and returns its results.
+func (m *Mock) called(method string, args ...interface{}) results {
+	m.mu.Lock()
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+	var fn ResultsFunc
+	if queue := m.results[method]; len(queue) > 0 {
+		fn = queue[0]
+		if len(queue) > 1 {
+			m.results[method] = queue[1:]
+		}
+	}
+	m.mu.Unlock()
+
+	if fn == nil {
+		return results{method: method}
+	}
+	return results{method: method, values: fn(args...)}
+}
+
+type results struct {
+	method string
+	values []interface{}
+}
+
+func (r results) get(i int) interface{} {
+	if i >= len(r.values) {
+		return nil
+	}
+	return r.values[i]
+}
+
+// value stores the i-th result in the variable pointed by ptr, which is left unchanged if the
+// result is nil. It panics if the result has the wrong type.
+func (r results) value(i int, ptr interface{}) {
+	v := r.get(i)
+	if v == nil {
+		return
+	}
+	dst := reflect.ValueOf(ptr).Elem()
+	src := reflect.ValueOf(v)
+	if !src.Type().AssignableTo(dst.Type()) {
+		panic(fmt.Sprintf("streammock: result %d of %s is a %T, not a %s", i, r.method, v, dst.Type()))
+	}
+	dst.Set(src)
+}
+
+// err returns the i-th result as an error. It panics if the result is not an error.
+func (r results) err(i int) error {
+	v := r.get(i)
+	if v == nil {
+		return nil
+	}
+	err, ok := v.(error)
+	if !ok {
+		panic(fmt.Sprintf("streammock: result %d of %s is a %T, not an error", i, r.method, v))
+	}
+	return err
+}
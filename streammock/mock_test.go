@@ -0,0 +1,80 @@
+package streammock_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	stream "github.com/GetStream/stream-chat-go/v4"
+	"github.com/GetStream/stream-chat-go/v4/streammock"
+)
+
+// greet is code under test, depending on the interfaces.
+func greet(ctx context.Context, c stream.ChatClient, userID string) error {
+	if _, err := c.UpsertUser(ctx, &stream.User{ID: userID}); err != nil {
+		return err
+	}
+	_, err := c.ChannelAPI("messaging", "welcome").SendMessage(ctx, &stream.Message{Text: "welcome " + userID}, "bot")
+	return err
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	unavailable := errors.New("unavailable")
+
+	c := streammock.NewClient()
+	ch := c.Channel("messaging", "welcome")
+	ch.On("SendMessage", nil, unavailable)
+	ch.On("SendMessage", &stream.Message{ID: "msg"}, nil)
+
+	require.Equal(t, unavailable, greet(ctx, c, "tommaso"), "the programmed error is returned")
+	require.NoError(t, greet(ctx, c, "tommaso"))
+	require.NoError(t, greet(ctx, c, "tommaso"), "the last results are repeated")
+
+	calls := c.CallsTo("UpsertUser")
+	require.Len(t, calls, 3)
+	require.Equal(t, &stream.User{ID: "tommaso"}, calls[0].Args[0])
+
+	sent := ch.Calls()
+	require.Len(t, sent, 3)
+	require.Equal(t, "SendMessage", sent[0].Method)
+	require.Equal(t, "welcome tommaso", sent[0].Args[0].(*stream.Message).Text)
+	require.Equal(t, "bot", sent[0].Args[1])
+	require.Empty(t, sent[0].Args[2], "variadic arguments are recorded as a slice")
+	require.Same(t, ch, c.ChannelAPI("messaging", "welcome"))
+
+	c.OnFunc("GetMessage", func(args ...interface{}) []interface{} {
+		return []interface{}{&stream.Message{ID: args[0].(string)}, nil}
+	})
+	msg, err := c.GetMessage(ctx, "msg-id")
+	require.NoError(t, err)
+	require.Equal(t, "msg-id", msg.ID)
+
+	created, err := c.CreateChannelAPI(ctx, "messaging", "general", "bot", nil)
+	require.NoError(t, err)
+	require.Same(t, c.Channel("messaging", "general"), created, "the channel mock is returned")
+	require.NoError(t, created.AddMembers(ctx, []string{"tommaso"}, nil, nil))
+	require.Len(t, c.Channel("messaging", "general").CallsTo("AddMembers"), 1)
+	require.Equal(t, "messaging:general", created.Data().CID)
+
+	c.On("QueryChannelsAPI", []stream.ChannelAPI{created}, nil)
+	channels, err := c.QueryChannelsAPI(ctx, &stream.QueryOption{})
+	require.NoError(t, err)
+	require.Equal(t, []stream.ChannelAPI{created}, channels)
+
+	c.On("VerifyWebhook", true)
+	require.True(t, c.VerifyWebhook([]byte("body"), []byte("signature")))
+
+	c.Reset()
+	require.Empty(t, c.Calls())
+	msg, err = c.GetMessage(ctx, "msg-id")
+	require.NoError(t, err)
+	require.Nil(t, msg, "zero values are returned without programmed results")
+
+	c.On("GetMessage", "not a message", nil)
+	require.PanicsWithValue(t, "streammock: result 0 of GetMessage is a string, not a *stream_chat.Message", func() {
+		_, _ = c.GetMessage(ctx, "msg-id")
+	})
+}
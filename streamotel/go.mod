@@ -0,0 +1,13 @@
+module github.com/GetStream/stream-chat-go/v4/streamotel
+
+go 1.15
+
+require (
+	github.com/GetStream/stream-chat-go/v4 v4.0.0
+	github.com/stretchr/testify v1.7.0
+	go.opentelemetry.io/otel v1.0.1
+	go.opentelemetry.io/otel/sdk v1.0.1
+	go.opentelemetry.io/otel/trace v1.0.1
+)
+
+replace github.com/GetStream/stream-chat-go/v4 => ../
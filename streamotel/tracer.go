@@ -0,0 +1,95 @@
+// Package streamotel creates OpenTelemetry spans for the API calls of the stream_chat client.
+//
+//	client, err := stream_chat.NewClient(apiKey, apiSecret,
+//		stream_chat.WithTracer(streamotel.NewTracer(nil)))
+//
+// Every API call creates a client span named after the API endpoint, like "SendMessage" or
+// "QueryChannels", with the following attributes when they are known:
+//
+//	http.method, http.target, http.status_code
+//	stream_chat.channel_type, stream_chat.channel_id, stream_chat.user_id, stream_chat.message_id
+//	stream_chat.error_code, the Stream error code of failed calls
+//
+// The trace context is propagated to the API with the W3C Trace Context headers.
+package streamotel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	stream "github.com/GetStream/stream-chat-go/v4"
+)
+
+const instrumentationName = "github.com/GetStream/stream-chat-go/v4"
+
+// Tracer is a stream_chat.Tracer creating OpenTelemetry spans.
+type Tracer struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+var _ stream.Tracer = (*Tracer)(nil)
+
+// NewTracer returns a Tracer creating spans with provider, the global TracerProvider if nil.
+func NewTracer(provider trace.TracerProvider) *Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return &Tracer{
+		tracer:     provider.Tracer(instrumentationName, trace.WithInstrumentationVersion(stream.Version())),
+		propagator: propagation.TraceContext{},
+	}
+}
+
+// Start implements stream_chat.Tracer.
+func (t *Tracer) Start(ctx context.Context, operation string, attrs stream.SpanAttributes) (context.Context, stream.Span) {
+	kv := []attribute.KeyValue{
+		attribute.String("http.method", attrs.Method),
+		attribute.String("http.target", attrs.Path),
+	}
+	for _, a := range []struct{ key, value string }{
+		{"stream_chat.channel_type", attrs.ChannelType},
+		{"stream_chat.channel_id", attrs.ChannelID},
+		{"stream_chat.user_id", attrs.UserID},
+		{"stream_chat.message_id", attrs.MessageID},
+	} {
+		if a.value != "" {
+			kv = append(kv, attribute.String(a.key, a.value))
+		}
+	}
+
+	ctx, span := t.tracer.Start(ctx, operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(kv...))
+	return ctx, otelSpan{span}
+}
+
+// Inject implements stream_chat.Tracer.
+func (t *Tracer) Inject(ctx context.Context, header http.Header) {
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// otelSpan adapts an OpenTelemetry span to stream_chat.Span.
+type otelSpan struct {
+	span trace.Span
+}
+
+func (s otelSpan) End(statusCode int, err error) {
+	if statusCode != 0 {
+		s.span.SetAttributes(attribute.Int("http.status_code", statusCode))
+	}
+	if err != nil {
+		var apiErr *stream.APIError
+		if errors.As(err, &apiErr) {
+			s.span.SetAttributes(attribute.Int("stream_chat.error_code", apiErr.Code))
+		}
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
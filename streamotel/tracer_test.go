@@ -0,0 +1,77 @@
+package streamotel_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	stream "github.com/GetStream/stream-chat-go/v4"
+	"github.com/GetStream/stream-chat-go/v4/streamotel"
+)
+
+func TestTracer(t *testing.T) {
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		if r.Method == http.MethodGet {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":16,"message":"message does not exist"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"message":{"id":"msg","text":"hello"}}`))
+	}))
+	defer srv.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	c, err := stream.NewClient("key", "secret", stream.WithBaseURL(srv.URL),
+		stream.WithTracer(streamotel.NewTracer(provider)))
+	require.NoError(t, err)
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	ch := c.Channel("messaging", "general")
+	_, err = ch.SendMessage(ctx, &stream.Message{ID: "msg", Text: "hello"}, "tommaso")
+	require.NoError(t, err)
+	_, err = c.GetMessage(ctx, "missing")
+	require.Error(t, err)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	send := spans[0]
+	require.Equal(t, "SendMessage", send.Name())
+	require.Equal(t, trace.SpanKindClient, send.SpanKind())
+	require.Equal(t, parent.SpanContext().TraceID(), send.SpanContext().TraceID())
+	require.Equal(t, parent.SpanContext().SpanID(), send.Parent().SpanID())
+	require.Subset(t, send.Attributes(), []attribute.KeyValue{
+		attribute.String("http.method", http.MethodPost),
+		attribute.String("stream_chat.channel_type", "messaging"),
+		attribute.String("stream_chat.channel_id", "general"),
+		attribute.String("stream_chat.user_id", "tommaso"),
+		attribute.String("stream_chat.message_id", "msg"),
+		attribute.Int("http.status_code", http.StatusOK),
+	})
+	require.Equal(t, codes.Unset, send.Status().Code)
+
+	get := spans[1]
+	require.Equal(t, "GetMessage", get.Name())
+	require.Subset(t, get.Attributes(), []attribute.KeyValue{
+		attribute.String("stream_chat.message_id", "missing"),
+		attribute.Int("http.status_code", http.StatusNotFound),
+		attribute.Int("stream_chat.error_code", stream.ErrorCodeDoesNotExist),
+	})
+	require.Equal(t, codes.Error, get.Status().Code)
+
+	want := "00-" + get.SpanContext().TraceID().String() + "-" + get.SpanContext().SpanID().String() + "-01"
+	require.Equal(t, want, traceparent, "the trace context is propagated")
+}
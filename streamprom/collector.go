@@ -0,0 +1,106 @@
+// Package streamprom exports the metrics of the stream_chat client to Prometheus.
+//
+//	collector, err := streamprom.NewCollector(prometheus.DefaultRegisterer)
+//	if err != nil {
+//		return err
+//	}
+//	client, err := stream_chat.NewClient(apiKey, apiSecret, stream_chat.WithMetrics(collector))
+//
+// The following metrics are registered:
+//
+//	stream_chat_requests_total{endpoint,method,status}        API calls, by status of the last response
+//	stream_chat_request_duration_seconds{endpoint,method}     duration of the API calls, retries included
+//	stream_chat_request_errors_total{endpoint,method,status}  failed API calls, status is "error" without response
+//	stream_chat_request_retries_total{endpoint,method}        retried attempts
+//	stream_chat_rate_limit_remaining{endpoint}                remaining calls in the rate limit window, last seen
+package streamprom
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	stream "github.com/GetStream/stream-chat-go/v4"
+)
+
+const namespace = "stream_chat"
+
+// Collector is a stream_chat.MetricsCollector recording Prometheus metrics.
+type Collector struct {
+	requests  *prometheus.CounterVec
+	duration  *prometheus.HistogramVec
+	errors    *prometheus.CounterVec
+	retries   *prometheus.CounterVec
+	remaining *prometheus.GaugeVec
+}
+
+var _ stream.MetricsCollector = (*Collector)(nil)
+
+// NewCollector returns a Collector with its metrics registered in reg,
+// prometheus.DefaultRegisterer if nil.
+func NewCollector(reg prometheus.Registerer) (*Collector, error) {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+
+	c := &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "Number of API calls, by status code of the last response.",
+		}, []string{"endpoint", "method", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Duration of the API calls, including retries.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"endpoint", "method"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "request_errors_total",
+			Help:      "Number of failed API calls, by status code or \"error\" when no response was received.",
+		}, []string{"endpoint", "method", "status"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "request_retries_total",
+			Help:      "Number of retried attempts of API calls.",
+		}, []string{"endpoint", "method"}),
+		remaining: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "rate_limit_remaining",
+			Help:      "Remaining API calls in the current rate limit window, as last reported by the API.",
+		}, []string{"endpoint"}),
+	}
+
+	for _, collector := range []prometheus.Collector{c.requests, c.duration, c.errors, c.retries, c.remaining} {
+		if err := reg.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// ObserveRequest implements stream_chat.MetricsCollector.
+func (c *Collector) ObserveRequest(endpoint, method string, statusCode int, duration time.Duration) {
+	status := "error"
+	if statusCode != 0 {
+		status = strconv.Itoa(statusCode)
+	}
+
+	c.requests.WithLabelValues(endpoint, method, status).Inc()
+	c.duration.WithLabelValues(endpoint, method).Observe(duration.Seconds())
+	if statusCode == 0 || statusCode >= 400 {
+		c.errors.WithLabelValues(endpoint, method, status).Inc()
+	}
+}
+
+// ObserveRetry implements stream_chat.MetricsCollector.
+func (c *Collector) ObserveRetry(endpoint, method string) {
+	c.retries.WithLabelValues(endpoint, method).Inc()
+}
+
+// ObserveRateLimit implements stream_chat.MetricsCollector.
+func (c *Collector) ObserveRateLimit(endpoint string, limit stream.RateLimitInfo) {
+	c.remaining.WithLabelValues(endpoint).Set(float64(limit.Remaining))
+}
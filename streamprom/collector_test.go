@@ -0,0 +1,63 @@
+package streamprom_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	stream "github.com/GetStream/stream-chat-go/v4"
+	"github.com/GetStream/stream-chat-go/v4/streamprom"
+	"github.com/GetStream/stream-chat-go/v4/streamtest"
+)
+
+func TestCollector(t *testing.T) {
+	ctx := context.Background()
+	srv := streamtest.NewServer("key", "secret")
+	defer srv.Close()
+
+	reg := prometheus.NewRegistry()
+	collector, err := streamprom.NewCollector(reg)
+	require.NoError(t, err)
+
+	c, err := stream.NewClient(srv.APIKey, srv.APISecret, stream.WithBaseURL(srv.URL), stream.WithMetrics(collector))
+	require.NoError(t, err)
+
+	_, err = c.GetAppConfig(ctx)
+	require.NoError(t, err)
+	_, err = c.GetMessage(ctx, "missing")
+	require.Error(t, err)
+
+	collector.ObserveRetry("GetMessage", http.MethodGet)
+
+	expected := `
+# HELP stream_chat_request_errors_total Number of failed API calls, by status code or "error" when no response was received.
+# TYPE stream_chat_request_errors_total counter
+stream_chat_request_errors_total{endpoint="GetMessage",method="GET",status="404"} 1
+# HELP stream_chat_request_retries_total Number of retried attempts of API calls.
+# TYPE stream_chat_request_retries_total counter
+stream_chat_request_retries_total{endpoint="GetMessage",method="GET"} 1
+# HELP stream_chat_requests_total Number of API calls, by status code of the last response.
+# TYPE stream_chat_requests_total counter
+stream_chat_requests_total{endpoint="GetApp",method="GET",status="200"} 1
+stream_chat_requests_total{endpoint="GetMessage",method="GET",status="404"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"stream_chat_requests_total", "stream_chat_request_errors_total", "stream_chat_request_retries_total"))
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	series := make(map[string]int)
+	for _, f := range families {
+		series[f.GetName()] = len(f.GetMetric())
+	}
+	require.Equal(t, 2, series["stream_chat_request_duration_seconds"])
+	require.Equal(t, 2, series["stream_chat_rate_limit_remaining"])
+
+	_, err = streamprom.NewCollector(reg)
+	require.Error(t, err, "metrics are registered once")
+}
@@ -0,0 +1,11 @@
+module github.com/GetStream/stream-chat-go/v4/streamprom
+
+go 1.14
+
+require (
+	github.com/GetStream/stream-chat-go/v4 v4.0.0
+	github.com/prometheus/client_golang v1.11.1
+	github.com/stretchr/testify v1.7.0
+)
+
+replace github.com/GetStream/stream-chat-go/v4 => ../
@@ -0,0 +1,118 @@
+package streamtest
+
+import (
+	"strings"
+)
+
+func (s *Server) getApp(r *request) (interface{}, error) {
+	app := copyObject(s.app)
+
+	configs := object{}
+	for name, ct := range s.channelTypes {
+		configs[name] = removeFields(ct, []string{"permissions"})
+	}
+	app["channel_configs"] = configs
+	app["policies"] = object{}
+	return object{"app": app}, nil
+}
+
+func (s *Server) updateApp(r *request) (interface{}, error) {
+	app := copyObject(s.app)
+	push := copyObject(objectField(app, "push_notifications"))
+
+	for k, v := range r.body {
+		switch k {
+		case "apn_config":
+			push["apn"] = v
+		case "firebase_config":
+			push["firebase"] = v
+		default:
+			app[k] = v
+		}
+	}
+	app["push_notifications"] = push
+	s.app = app
+	return nil, nil
+}
+
+func (s *Server) getRateLimits(r *request) (interface{}, error) {
+	params := r.URL.Query()
+
+	platforms := []string{"server_side", "android", "ios", "web"}
+	var selected []string
+	for _, p := range platforms {
+		if params.Get(p) == "true" {
+			selected = append(selected, p)
+		}
+	}
+	if len(selected) == 0 {
+		selected = platforms
+	}
+
+	endpoints := s.endpoints
+	if v := params.Get("endpoints"); v != "" {
+		endpoints = strings.Split(v, ",")
+	}
+
+	resp := object{}
+	for _, p := range selected {
+		limits := object{}
+		for _, name := range endpoints {
+			rl := s.rateLimit(name)
+			remaining := int64(defaultRateLimit)
+			if p == "server_side" {
+				// only the server side calls are made to this server
+				remaining -= rl.used
+			}
+			limits[name] = object{"limit": defaultRateLimit, "remaining": remaining, "reset": rl.reset.Unix()}
+		}
+		resp[p] = limits
+	}
+	return resp, nil
+}
+
+func (s *Server) getTask(r *request) (interface{}, error) {
+	task, ok := s.tasks[r.param(0)]
+	if !ok {
+		return nil, errNotFound("task %q does not exist", r.param(0))
+	}
+	return copyObject(task), nil
+}
+
+func (s *Server) exportChannels(r *request) (interface{}, error) {
+	list, _ := r.body["channels"].([]interface{})
+	if len(list) == 0 {
+		return nil, errInput("channels is a required field")
+	}
+
+	for _, v := range list {
+		ch, _ := v.(map[string]interface{})
+		cid := stringField(ch, "type") + ":" + stringField(ch, "id")
+		if _, ok := s.channels[cid]; !ok {
+			return nil, errInput("channel %q does not exist", cid)
+		}
+	}
+
+	id := s.newTask(object{})
+	s.tasks[id]["result"] = object{"url": s.URL + "/exports/" + id + ".json"}
+	return object{"task_id": id}, nil
+}
+
+func (s *Server) deleteChannels(r *request) (interface{}, error) {
+	cids := stringList(r.body, "cids")
+	if len(cids) == 0 {
+		return nil, errInput("cids is a required field")
+	}
+
+	result := object{}
+	for _, cid := range cids {
+		ch, ok := s.channels[cid]
+		if !ok {
+			result[cid] = object{"status": "error", "error": "channel does not exist"}
+			continue
+		}
+		s.removeChannel(ch)
+		result[cid] = object{"status": "ok"}
+	}
+	return object{"task_id": s.newTask(result)}, nil
+}
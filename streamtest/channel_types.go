@@ -0,0 +1,243 @@
+package streamtest
+
+import (
+	"sort"
+	"time"
+)
+
+//nolint: gochecknoglobals
+var (
+	// defaultChannelTypes are the channel types every application has.
+	defaultChannelTypes = []string{"messaging", "team", "livestream", "commerce", "gaming"}
+
+	// defaultCommands are the built-in commands, which are also the ones a channel type
+	// created with the "all" commands has.
+	defaultCommands = []object{
+		{"name": "giphy", "description": "Post a random gif to the channel", "args": "[text]", "set": "fun_set"},
+		{"name": "ban", "description": "Ban a user", "args": "[@username] [text]", "set": "moderation_set"},
+		{"name": "unban", "description": "Unban a user", "args": "[@username]", "set": "moderation_set"},
+		{"name": "mute", "description": "Mute a user", "args": "[@username]", "set": "moderation_set"},
+		{"name": "unmute", "description": "Unmute a user", "args": "[@username]", "set": "moderation_set"},
+	}
+)
+
+// newChannelType returns a channel type with the default configuration overridden by config.
+func newChannelType(name string, config object, now time.Time) object {
+	ct := object{
+		"name":               name,
+		"typing_events":      true,
+		"read_events":        true,
+		"connect_events":     true,
+		"search":             true,
+		"reactions":          true,
+		"replies":            true,
+		"mutes":              true,
+		"push_notifications": true,
+		"uploads":            true,
+		"url_enrichment":     true,
+		"custom_events":      false,
+		"message_retention":  "infinite",
+		"max_message_length": 5000,
+		"automod":            "disabled",
+		"automod_behavior":   "flag",
+		"blocklist_behavior": "flag",
+		"commands":           defaultCommandNames(),
+		"permissions":        []interface{}{},
+	}
+	for k, v := range config {
+		ct[k] = v
+	}
+	ct["created_at"] = now
+	ct["updated_at"] = now
+	return ct
+}
+
+func defaultCommandNames() []interface{} {
+	names := make([]interface{}, 0, len(defaultCommands))
+	for _, cmd := range defaultCommands {
+		names = append(names, cmd["name"])
+	}
+	return names
+}
+
+// commandNames validates the commands of a channel type, expanding "all" to the default commands.
+func (s *Server) commandNames(v interface{}) ([]interface{}, error) {
+	list, _ := v.([]interface{})
+	names := make([]interface{}, 0, len(list))
+	for _, item := range list {
+		name, _ := item.(string)
+		switch {
+		case name == "all":
+			return defaultCommandNames(), nil
+		case s.commands[name] == nil:
+			return nil, errInput("command %q does not exist", name)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// channelTypeJSON returns the channel type with its commands as objects, as returned by
+// the get and list endpoints.
+func (s *Server) channelTypeJSON(ct object) object {
+	out := copyObject(ct)
+	names, _ := ct["commands"].([]interface{})
+	commands := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		if cmd, ok := s.commands[name.(string)]; ok {
+			commands = append(commands, cmd)
+		}
+	}
+	out["commands"] = commands
+	return out
+}
+
+func (s *Server) listChannelTypes(r *request) (interface{}, error) {
+	types := object{}
+	for name, ct := range s.channelTypes {
+		types[name] = s.channelTypeJSON(ct)
+	}
+	return object{"channel_types": types}, nil
+}
+
+func (s *Server) createChannelType(r *request) (interface{}, error) {
+	name := stringField(r.body, "name")
+	if name == "" {
+		return nil, errInput("name is a required field")
+	}
+	if _, ok := s.channelTypes[name]; ok {
+		return nil, errInput("channel type %q already exists", name)
+	}
+
+	config := removeFields(r.body, []string{"created_at", "updated_at"})
+	if _, ok := config["commands"]; ok {
+		commands, err := s.commandNames(config["commands"])
+		if err != nil {
+			return nil, err
+		}
+		config["commands"] = commands
+	}
+
+	ct := newChannelType(name, config, s.now())
+	s.channelTypes[name] = ct
+	return copyObject(ct), nil
+}
+
+func (s *Server) getChannelType(r *request) (interface{}, error) {
+	ct, ok := s.channelTypes[r.param(0)]
+	if !ok {
+		return nil, errNotFound("channel type %q does not exist", r.param(0))
+	}
+	return s.channelTypeJSON(ct), nil
+}
+
+func (s *Server) updateChannelType(r *request) (interface{}, error) {
+	old, ok := s.channelTypes[r.param(0)]
+	if !ok {
+		return nil, errNotFound("channel type %q does not exist", r.param(0))
+	}
+
+	ct := copyObject(old)
+	for k, v := range removeFields(r.body, []string{"name", "created_at", "updated_at"}) {
+		if k == "commands" {
+			commands, err := s.commandNames(v)
+			if err != nil {
+				return nil, err
+			}
+			v = commands
+		}
+		ct[k] = v
+	}
+	ct["updated_at"] = s.now()
+	s.channelTypes[r.param(0)] = ct
+	return copyObject(ct), nil
+}
+
+func (s *Server) deleteChannelType(r *request) (interface{}, error) {
+	name := r.param(0)
+	if _, ok := s.channelTypes[name]; !ok {
+		return nil, errNotFound("channel type %q does not exist", name)
+	}
+	for _, ch := range s.channels {
+		if ch.typ == name {
+			return nil, errInput("channel type %q has channels, delete them first", name)
+		}
+	}
+	delete(s.channelTypes, name)
+	return nil, nil
+}
+
+// reservedCommandFields are the command fields managed by the server.
+//nolint: gochecknoglobals
+var reservedCommandFields = []string{"name", "created_at", "updated_at"}
+
+func (s *Server) listCommands(r *request) (interface{}, error) {
+	names := make([]string, 0, len(s.commands))
+	for name := range s.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	commands := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		commands = append(commands, s.commands[name])
+	}
+	return object{"commands": commands}, nil
+}
+
+func (s *Server) createCommand(r *request) (interface{}, error) {
+	name := stringField(r.body, "name")
+	if name == "" || stringField(r.body, "description") == "" {
+		return nil, errInput("name and description are required fields")
+	}
+	if _, ok := s.commands[name]; ok {
+		return nil, errInput("command %q already exists", name)
+	}
+
+	now := s.now()
+	cmd := object{"name": name, "args": "", "set": ""}
+	for k, v := range removeFields(r.body, reservedCommandFields) {
+		cmd[k] = v
+	}
+	cmd["created_at"] = now
+	cmd["updated_at"] = now
+	s.commands[name] = cmd
+	return object{"command": cmd}, nil
+}
+
+func (s *Server) getCommand(r *request) (interface{}, error) {
+	cmd, ok := s.commands[r.param(0)]
+	if !ok {
+		return nil, errNotFound("command %q does not exist", r.param(0))
+	}
+	return copyObject(cmd), nil
+}
+
+func (s *Server) updateCommand(r *request) (interface{}, error) {
+	old, ok := s.commands[r.param(0)]
+	if !ok {
+		return nil, errNotFound("command %q does not exist", r.param(0))
+	}
+
+	cmd := copyObject(old)
+	for k, v := range removeFields(r.body, reservedCommandFields) {
+		cmd[k] = v
+	}
+	cmd["updated_at"] = s.now()
+	s.commands[r.param(0)] = cmd
+	return object{"command": cmd}, nil
+}
+
+func (s *Server) deleteCommand(r *request) (interface{}, error) {
+	name := r.param(0)
+	if _, ok := s.commands[name]; !ok {
+		return nil, errNotFound("command %q does not exist", name)
+	}
+	for _, cmd := range defaultCommands {
+		if cmd["name"] == name {
+			return nil, errInput("built-in command %q cannot be deleted", name)
+		}
+	}
+	delete(s.commands, name)
+	return object{"name": name}, nil
+}
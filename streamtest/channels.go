@@ -0,0 +1,545 @@
+package streamtest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	roleMember    = "member"
+	roleModerator = "moderator"
+	roleOwner     = "owner"
+
+	defaultMessageLimit = 25
+	defaultMemberLimit  = 100
+)
+
+// reservedChannelFields are the channel fields which are not custom data.
+//nolint: gochecknoglobals
+var reservedChannelFields = []string{
+	"id", "type", "cid", "created_by", "created_by_id", "members", "invites", "member_count",
+	"config", "frozen", "disabled", "created_at", "updated_at", "last_message_at", "truncated_at",
+	"deleted_at", "messages", "read", "team",
+}
+
+// channelKey returns the key of the channel in the state from the path parameters of the request.
+func channelKey(r *request) string {
+	return r.param(0) + ":" + r.param(1)
+}
+
+func (s *Server) channel(r *request) (*channel, error) {
+	ch, ok := s.channels[channelKey(r)]
+	if !ok {
+		return nil, errNotFound("channel %q does not exist", channelKey(r))
+	}
+	return ch, nil
+}
+
+func (s *Server) getOrCreateChannel(r *request) (interface{}, error) {
+	typ := r.param(0)
+	if _, ok := s.channelTypes[typ]; !ok {
+		return nil, errInput("channel type %q does not exist", typ)
+	}
+
+	data := objectField(r.body, "data")
+	members := stringList(data, "members", "user_id")
+
+	var id string
+	if len(r.params) > 1 {
+		id = r.param(1)
+	}
+	if id == "" {
+		if len(members) == 0 {
+			return nil, errInput("either channel id or data.members must be provided")
+		}
+		id = distinctChannelID(members)
+	}
+
+	ch, ok := s.channels[typ+":"+id]
+	if !ok {
+		var err error
+		if ch, err = s.createChannel(typ, id, data, members); err != nil {
+			return nil, err
+		}
+	}
+
+	messageLimit := defaultMessageLimit
+	if messages := objectField(r.body, "messages"); messages["limit"] != nil {
+		messageLimit = intField(messages, "limit")
+	}
+	memberLimit := defaultMemberLimit
+	if v := intField(objectField(r.body, "members"), "limit"); v > 0 {
+		memberLimit = v
+	}
+	return s.channelState(ch, messageLimit, memberLimit), nil
+}
+
+// distinctChannelID returns the ID the API gives to a channel created without ID, derived
+// from its members.
+func distinctChannelID(members []string) string {
+	sorted := append([]string(nil), members...)
+	sort.Strings(sorted)
+	h := sha256.Sum256([]byte(strings.Join(sorted, ",")))
+	return "!members-" + hex.EncodeToString(h[:])[:32]
+}
+
+func (s *Server) createChannel(typ, id string, data object, members []string) (*channel, error) {
+	createdBy := stringField(data, "created_by_id")
+	if createdBy == "" {
+		createdBy = idOf(data["created_by"])
+	}
+	if createdBy == "" {
+		return nil, errInput("either data.created_by or data.created_by_id must be provided when using server side auth")
+	}
+	if err := s.existingUsers([]string{createdBy}, "channel.created_by"); err != nil {
+		return nil, err
+	}
+	if err := s.existingUsers(members, "channel.members"); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	ch := &channel{
+		typ:       typ,
+		id:        id,
+		data:      removeFields(data, reservedChannelFields),
+		createdBy: createdBy,
+		frozen:    boolField(data, "frozen"),
+		disabled:  boolField(data, "disabled"),
+		reads:     make(map[string]time.Time),
+		hidden:    make(map[string]bool),
+		createdAt: now,
+		updatedAt: now,
+	}
+
+	invites := stringList(data, "invites")
+	for _, userID := range members {
+		s.addMember(ch, userID, contains(invites, userID))
+	}
+	for _, userID := range invites {
+		s.addMember(ch, userID, true)
+	}
+
+	s.channels[ch.cid()] = ch
+	return ch, nil
+}
+
+// addMember adds the user to the channel if it is not a member yet.
+func (s *Server) addMember(ch *channel, userID string, invited bool) *member {
+	if m := ch.member(userID); m != nil {
+		return m
+	}
+
+	now := s.now()
+	m := &member{userID: userID, role: roleMember, invited: invited, createdAt: now, updatedAt: now}
+	if userID == ch.createdBy {
+		m.role = roleOwner
+	}
+	ch.members = append(ch.members, m)
+	return m
+}
+
+func (s *Server) updateChannel(r *request) (interface{}, error) {
+	ch, err := s.channel(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, field := range []string{"add_members", "add_moderators", "invites"} {
+		if err := s.existingUsers(stringList(r.body, field, "user_id"), field); err != nil {
+			return nil, err
+		}
+	}
+
+	now := s.now()
+	if data, ok := r.body["data"].(map[string]interface{}); ok {
+		ch.data = removeFields(data, reservedChannelFields)
+		ch.frozen = boolField(data, "frozen")
+		ch.disabled = boolField(data, "disabled")
+	}
+	for _, userID := range stringList(r.body, "add_members", "user_id") {
+		s.addMember(ch, userID, false)
+	}
+	for _, userID := range stringList(r.body, "invites") {
+		s.addMember(ch, userID, true).invited = true
+	}
+	for _, userID := range stringList(r.body, "remove_members") {
+		for i, m := range ch.members {
+			if m.userID == userID {
+				ch.members = append(ch.members[:i], ch.members[i+1:]...)
+				break
+			}
+		}
+	}
+	for _, userID := range stringList(r.body, "add_moderators") {
+		m := s.addMember(ch, userID, false)
+		m.role = roleModerator
+		m.updatedAt = now
+	}
+	for _, userID := range stringList(r.body, "demote_moderators") {
+		if m := ch.member(userID); m != nil {
+			m.role = roleMember
+			m.updatedAt = now
+		}
+	}
+
+	if boolField(r.body, "accept_invite") || boolField(r.body, "reject_invite") {
+		userID := stringField(r.body, "user_id")
+		m := ch.member(userID)
+		if m == nil || !m.invited {
+			return nil, errInput("user %q is not invited to the channel", userID)
+		}
+		if boolField(r.body, "accept_invite") {
+			m.inviteAcceptedAt = &now
+		} else {
+			m.inviteRejectedAt = &now
+		}
+		m.updatedAt = now
+	}
+	ch.updatedAt = now
+
+	resp := object{
+		"channel": s.channelJSON(ch),
+		"members": s.membersJSON(ch, 0),
+	}
+	if msg := objectField(r.body, "message"); msg != nil {
+		m, err := s.newMessage(ch, msg)
+		if err != nil {
+			return nil, err
+		}
+		resp["message"] = s.messageJSON(m)
+	}
+	return resp, nil
+}
+
+func (s *Server) updateChannelPartial(r *request) (interface{}, error) {
+	ch, err := s.channel(r)
+	if err != nil {
+		return nil, err
+	}
+
+	data := copyObject(ch.data)
+	for k, v := range objectField(r.body, "set") {
+		if !isReserved(k, reservedChannelFields) {
+			setPath(data, k, v)
+		}
+	}
+	unset, _ := r.body["unset"].([]interface{})
+	for _, k := range unset {
+		if k, ok := k.(string); ok {
+			unsetPath(data, k)
+		}
+	}
+	ch.data = data
+	ch.updatedAt = s.now()
+
+	return object{"channel": s.channelJSON(ch), "members": s.membersJSON(ch, 0)}, nil
+}
+
+func (s *Server) deleteChannel(r *request) (interface{}, error) {
+	ch, err := s.channel(r)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := s.channelJSON(ch)
+	resp["deleted_at"] = s.now()
+	s.removeChannel(ch)
+	return object{"channel": resp}, nil
+}
+
+func (s *Server) removeChannel(ch *channel) {
+	for _, m := range ch.messages {
+		delete(s.messages, m.id)
+	}
+	delete(s.channels, ch.cid())
+}
+
+func (s *Server) truncateChannel(r *request) (interface{}, error) {
+	ch, err := s.channel(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range ch.messages {
+		delete(s.messages, m.id)
+	}
+	now := s.now()
+	ch.messages = nil
+	ch.lastMessageAt = nil
+	ch.truncatedAt = &now
+	ch.updatedAt = now
+	return object{"channel": s.channelJSON(ch)}, nil
+}
+
+func (s *Server) importMessages(r *request) (interface{}, error) {
+	ch, err := s.channel(r)
+	if err != nil {
+		return nil, err
+	}
+
+	list, _ := r.body["messages"].([]interface{})
+	if len(list) == 0 {
+		return nil, errInput("messages is a required field")
+	}
+
+	messages := make([]*message, 0, len(list))
+	for _, v := range list {
+		fields, _ := v.(map[string]interface{})
+		m, err := s.newMessage(ch, fields)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, m)
+	}
+
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].createdAt.Before(messages[j].createdAt)
+	})
+	resp := make([]interface{}, 0, len(messages))
+	for _, m := range messages {
+		resp = append(resp, s.messageJSON(m))
+	}
+	return object{"messages": resp}, nil
+}
+
+func (s *Server) markRead(r *request) (interface{}, error) {
+	ch, err := s.channel(r)
+	if err != nil {
+		return nil, err
+	}
+
+	userID := stringField(objectField(r.body, "user"), "id")
+	if userID == "" {
+		return nil, errInput("user is a required field")
+	}
+	now := s.now()
+	ch.reads[userID] = now
+
+	return object{"event": object{
+		"type":       "message.read",
+		"cid":        ch.cid(),
+		"channel_id": ch.id,
+		"user":       s.userJSON(userID),
+		"created_at": now,
+	}}, nil
+}
+
+func (s *Server) markAllRead(r *request) (interface{}, error) {
+	userID := stringField(objectField(r.body, "user"), "id")
+	if userID == "" {
+		return nil, errInput("user is a required field")
+	}
+
+	now := s.now()
+	for _, ch := range s.channels {
+		if ch.member(userID) != nil {
+			ch.reads[userID] = now
+		}
+	}
+	return nil, nil
+}
+
+func (s *Server) showChannel(r *request) (interface{}, error) {
+	return s.setHidden(r, false)
+}
+
+func (s *Server) hideChannel(r *request) (interface{}, error) {
+	return s.setHidden(r, true)
+}
+
+func (s *Server) setHidden(r *request, hidden bool) (interface{}, error) {
+	ch, err := s.channel(r)
+	if err != nil {
+		return nil, err
+	}
+
+	userID := stringField(r.body, "user_id")
+	if ch.member(userID) == nil {
+		return nil, errInput("user %q is not a member of the channel", userID)
+	}
+	ch.hidden[userID] = hidden
+	return nil, nil
+}
+
+func (s *Server) sendEvent(r *request) (interface{}, error) {
+	ch, err := s.channel(r)
+	if err != nil {
+		return nil, err
+	}
+
+	event := objectField(r.body, "event")
+	if stringField(event, "type") == "" {
+		return nil, errInput("event.type is a required field")
+	}
+	event = copyObject(event)
+	event["cid"] = ch.cid()
+	event["channel_id"] = ch.id
+	event["channel_type"] = ch.typ
+	event["created_at"] = s.now()
+	return object{"event": event}, nil
+}
+
+func (s *Server) queryChannels(r *request) (interface{}, error) {
+	userID := stringField(r.body, "user_id")
+
+	cids := make([]string, 0, len(s.channels))
+	for cid := range s.channels {
+		cids = append(cids, cid)
+	}
+	sort.Strings(cids)
+
+	docs := make([]object, len(cids))
+	for i, cid := range cids {
+		ch := s.channels[cid]
+		doc := toDocument(s.channelJSON(ch))
+		doc["members"] = ch.memberIDs()
+		if userID != "" {
+			doc["muted"] = s.channelMuted(userID, cid)
+			doc["hidden"] = ch.hidden[userID]
+		}
+		docs[i] = doc
+	}
+
+	filter := objectField(r.body, "filter_conditions")
+	if userID != "" {
+		if _, ok := filter["hidden"]; !ok {
+			filter = copyObject(filter)
+			filter["hidden"] = false
+		}
+	}
+
+	indexes, err := query(docs, filter, r.body["sort"],
+		object{"field": "last_message_at", "direction": -1},
+		object{"field": "created_at", "direction": -1})
+	if err != nil {
+		return nil, err
+	}
+
+	messageLimit := defaultMessageLimit
+	if _, ok := r.body["message_limit"]; ok {
+		messageLimit = intField(r.body, "message_limit")
+	}
+	memberLimit := defaultMemberLimit
+	if v := intField(r.body, "member_limit"); v > 0 {
+		memberLimit = v
+	}
+
+	start, end := paginate(len(indexes), r.body, 10)
+	channels := make([]interface{}, 0, end-start)
+	for _, i := range indexes[start:end] {
+		channels = append(channels, s.channelState(s.channels[cids[i]], messageLimit, memberLimit))
+	}
+	return object{"channels": channels}, nil
+}
+
+func (s *Server) queryMembers(r *request) (interface{}, error) {
+	q, err := r.payload()
+	if err != nil {
+		return nil, err
+	}
+
+	typ, id := stringField(q, "type"), stringField(q, "id")
+	if id == "" {
+		id = distinctChannelID(stringList(q, "members", "user_id"))
+	}
+	ch, ok := s.channels[typ+":"+id]
+	if !ok {
+		return nil, errNotFound("channel %q does not exist", typ+":"+id)
+	}
+
+	docs := make([]object, len(ch.members))
+	for i, m := range ch.members {
+		doc := toDocument(s.userJSON(m.userID))
+		for k, v := range toDocument(s.memberJSON(ch, m)) {
+			doc[k] = v
+		}
+		docs[i] = doc
+	}
+
+	indexes, err := query(docs, objectField(q, "filter_conditions"), q["sort"])
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := paginate(len(indexes), q, defaultMemberLimit)
+	members := make([]interface{}, 0, end-start)
+	for _, i := range indexes[start:end] {
+		members = append(members, s.memberJSON(ch, ch.members[i]))
+	}
+	return object{"members": members}, nil
+}
+
+func (s *Server) uploadFile(r *request) (interface{}, error) {
+	ch, err := s.channel(r)
+	if err != nil {
+		return nil, err
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		return nil, errInput("request must be a multipart form")
+	}
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, errInput("invalid multipart form: %v", err)
+	}
+
+	var user, fileName string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errInput("invalid multipart form: %v", err)
+		}
+
+		switch part.FormName() {
+		case "user":
+			b, _ := ioutil.ReadAll(part)
+			user = string(b)
+		case "file":
+			fileName = part.FileName()
+			if _, err := io.Copy(ioutil.Discard, part); err != nil {
+				return nil, errInput("cannot read file: %v", err)
+			}
+		}
+	}
+	if user == "" || user == "null" {
+		return nil, errInput("user is a required field")
+	}
+	if fileName == "" {
+		return nil, errInput("file is a required field")
+	}
+
+	location := s.URL + "/" + path.Join("uploads", ch.typ, ch.id, newID(), url.PathEscape(fileName))
+	s.files[location] = true
+	return object{"file": location}, nil
+}
+
+func (s *Server) deleteFile(r *request) (interface{}, error) {
+	location := r.URL.Query().Get("url")
+	if !s.files[location] {
+		return nil, errNotFound("file %q does not exist", location)
+	}
+	delete(s.files, location)
+	return nil, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
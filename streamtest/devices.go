@@ -0,0 +1,62 @@
+package streamtest
+
+import "strings"
+
+// pushProviders are the push providers accepted for devices.
+//nolint: gochecknoglobals
+var pushProviders = []string{"apn", "firebase", "huawei", "xiaomi"}
+
+func (s *Server) listDevices(r *request) (interface{}, error) {
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		return nil, errInput("user_id is a required field")
+	}
+
+	devices := make([]interface{}, 0, len(s.devices[userID]))
+	for _, d := range s.devices[userID] {
+		devices = append(devices, d)
+	}
+	return object{"devices": devices}, nil
+}
+
+func (s *Server) createDevice(r *request) (interface{}, error) {
+	id, userID := stringField(r.body, "id"), stringField(r.body, "user_id")
+	provider := stringField(r.body, "push_provider")
+	switch {
+	case id == "" || userID == "":
+		return nil, errInput("id and user_id are required fields")
+	case !contains(pushProviders, provider):
+		return nil, errInput("push_provider must be one of %s", strings.Join(pushProviders, ", "))
+	}
+	if _, ok := s.users[userID]; !ok {
+		return nil, errInput("user %q does not exist", userID)
+	}
+
+	s.removeDevice(userID, id)
+	s.devices[userID] = append(s.devices[userID], object{
+		"id":            id,
+		"user_id":       userID,
+		"push_provider": provider,
+		"created_at":    s.now(),
+	})
+	return nil, nil
+}
+
+func (s *Server) deleteDevice(r *request) (interface{}, error) {
+	params := r.URL.Query()
+	if !s.removeDevice(params.Get("user_id"), params.Get("id")) {
+		return nil, errNotFound("device %q does not exist", params.Get("id"))
+	}
+	return nil, nil
+}
+
+func (s *Server) removeDevice(userID, id string) bool {
+	devices := s.devices[userID]
+	for i, d := range devices {
+		if d["id"] == id {
+			s.devices[userID] = append(devices[:i], devices[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
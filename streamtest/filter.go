@@ -0,0 +1,321 @@
+package streamtest
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// match reports whether the document matches the filter conditions, which use the query
+// language of the API: https://getstream.io/chat/docs/go-golang/query_syntax/.
+func match(doc, filter object) (bool, error) {
+	for key, cond := range filter {
+		var ok bool
+		var err error
+
+		switch key {
+		case "$and", "$or", "$nor":
+			ok, err = matchLogical(doc, key, cond)
+		default:
+			ok, err = matchField(lookup(doc, key), cond)
+		}
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func matchLogical(doc object, op string, cond interface{}) (bool, error) {
+	filters, ok := cond.([]interface{})
+	if !ok {
+		return false, errInput("%s operator expects a list of filters", op)
+	}
+
+	matched := 0
+	for _, f := range filters {
+		sub, ok := f.(map[string]interface{})
+		if !ok {
+			return false, errInput("%s operator expects a list of filters", op)
+		}
+		ok, err := match(doc, sub)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			matched++
+		}
+	}
+
+	switch op {
+	case "$and":
+		return matched == len(filters), nil
+	case "$or":
+		return matched > 0, nil
+	default:
+		return matched == 0, nil
+	}
+}
+
+// matchField evaluates the condition on a field, which is either a value the field must be
+// equal to or an object of operators.
+func matchField(value, cond interface{}) (bool, error) {
+	ops, ok := cond.(map[string]interface{})
+	if !ok || !isOperatorObject(ops) {
+		return equal(value, cond), nil
+	}
+
+	for op, arg := range ops {
+		ok, err := matchOperator(value, op, arg)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func isOperatorObject(o object) bool {
+	for k := range o {
+		if !strings.HasPrefix(k, "$") {
+			return false
+		}
+	}
+	return len(o) > 0
+}
+
+func matchOperator(value interface{}, op string, arg interface{}) (bool, error) {
+	switch op {
+	case "$eq":
+		return equal(value, arg), nil
+	case "$ne":
+		return !equal(value, arg), nil
+	case "$in", "$nin":
+		list, ok := arg.([]interface{})
+		if !ok {
+			return false, errInput("%s operator expects a list", op)
+		}
+		found := false
+		for _, v := range list {
+			if equal(value, v) {
+				found = true
+				break
+			}
+		}
+		return found == (op == "$in"), nil
+	case "$gt":
+		return value != nil && compare(value, arg) > 0, nil
+	case "$gte":
+		return value != nil && compare(value, arg) >= 0, nil
+	case "$lt":
+		return value != nil && compare(value, arg) < 0, nil
+	case "$lte":
+		return value != nil && compare(value, arg) <= 0, nil
+	case "$exists":
+		exists, ok := arg.(bool)
+		if !ok {
+			return false, errInput("$exists operator expects a boolean")
+		}
+		return (value != nil) == exists, nil
+	case "$contains":
+		list, _ := value.([]interface{})
+		for _, v := range list {
+			if equal(v, arg) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "$autocomplete":
+		s, ok := arg.(string)
+		if !ok {
+			return false, errInput("$autocomplete operator expects a string")
+		}
+		return autocomplete(value, s), nil
+	case "$q":
+		s, ok := arg.(string)
+		if !ok {
+			return false, errInput("$q operator expects a string")
+		}
+		return fullText(value, s), nil
+	default:
+		return false, errInput("operator %s is not supported", op)
+	}
+}
+
+// lookup returns the value at a dotted path of the document, or nil if there is none.
+func lookup(doc object, path string) interface{} {
+	var v interface{} = doc
+	for _, k := range strings.Split(path, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[k]
+	}
+	return v
+}
+
+// equal compares JSON values. Lists are equal to the values they contain, so that
+// {"members": "id"} matches the channels the user is a member of.
+func equal(value, arg interface{}) bool {
+	if list, ok := value.([]interface{}); ok {
+		if _, ok := arg.([]interface{}); !ok {
+			for _, v := range list {
+				if equal(v, arg) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	if value == nil {
+		if b, ok := arg.(bool); ok {
+			// unset boolean fields are false
+			return !b
+		}
+	}
+	return reflect.DeepEqual(value, arg)
+}
+
+// compare orders JSON values of the same kind; strings holding times are compared as times.
+func compare(a, b interface{}) int {
+	switch a := a.(type) {
+	case float64:
+		if b, ok := b.(float64); ok {
+			switch {
+			case a < b:
+				return -1
+			case a > b:
+				return 1
+			}
+			return 0
+		}
+	case string:
+		if b, ok := b.(string); ok {
+			ta, errA := time.Parse(time.RFC3339Nano, a)
+			tb, errB := time.Parse(time.RFC3339Nano, b)
+			if errA == nil && errB == nil {
+				switch {
+				case ta.Before(tb):
+					return -1
+				case ta.After(tb):
+					return 1
+				}
+				return 0
+			}
+			return strings.Compare(a, b)
+		}
+	case bool:
+		if b, ok := b.(bool); ok && a != b {
+			if b {
+				return -1
+			}
+			return 1
+		}
+		return 0
+	case nil:
+		if b != nil {
+			return -1
+		}
+		return 0
+	}
+	if b == nil {
+		return 1
+	}
+	return 0
+}
+
+// autocomplete reports whether one of the words of the value starts with the prefix.
+func autocomplete(value interface{}, prefix string) bool {
+	s, ok := value.(string)
+	if !ok {
+		return false
+	}
+	prefix = strings.ToLower(prefix)
+	for _, w := range strings.Fields(strings.ToLower(s)) {
+		if strings.HasPrefix(w, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// fullText reports whether the value contains all the words of the query.
+func fullText(value interface{}, query string) bool {
+	s, ok := value.(string)
+	if !ok {
+		return false
+	}
+	words := make(map[string]bool)
+	for _, w := range strings.Fields(strings.ToLower(s)) {
+		words[w] = true
+	}
+	for _, w := range strings.Fields(strings.ToLower(query)) {
+		if !words[w] {
+			return false
+		}
+	}
+	return true
+}
+
+// query returns the indexes of the documents matching the filter conditions, sorted with the
+// sort option of the query, a list of {"field": name, "direction": 1 or -1} objects.
+// defaultSort is used when the query has no sort option.
+func query(docs []object, filter object, sortOption interface{}, defaultSort ...object) ([]int, error) {
+	var indexes []int
+	for i, doc := range docs {
+		ok, err := match(doc, filter)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			indexes = append(indexes, i)
+		}
+	}
+
+	var sorters []object
+	if list, ok := sortOption.([]interface{}); ok {
+		for _, s := range list {
+			if o, ok := s.(map[string]interface{}); ok && stringField(o, "field") != "" {
+				sorters = append(sorters, o)
+			}
+		}
+	}
+	if len(sorters) == 0 {
+		sorters = defaultSort
+	}
+
+	sort.SliceStable(indexes, func(i, j int) bool {
+		for _, s := range sorters {
+			field := stringField(s, "field")
+			c := compare(lookup(docs[indexes[i]], field), lookup(docs[indexes[j]], field))
+			if intField(s, "direction") < 0 {
+				c = -c
+			}
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+	return indexes, nil
+}
+
+// paginate returns the page of the items selected by the limit and offset of a query.
+func paginate(n int, q object, defaultLimit int) (start, end int) {
+	offset := intField(q, "offset")
+	limit := intField(q, "limit")
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	start = offset
+	if start > n {
+		start = n
+	}
+	end = start + limit
+	if end > n {
+		end = n
+	}
+	return start, end
+}
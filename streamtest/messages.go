@@ -0,0 +1,417 @@
+package streamtest
+
+import (
+	"encoding/base64"
+	"strconv"
+)
+
+// newMessage validates the message sent or imported in the channel and stores it.
+func (s *Server) newMessage(ch *channel, fields object) (*message, error) {
+	userID := idOf(fields["user"])
+	if userID == "" {
+		userID = stringField(fields, "user_id")
+	}
+	if userID == "" {
+		return nil, errInput("message.user or message.user_id is a required field")
+	}
+
+	id := stringField(fields, "id")
+	if id == "" {
+		id = newID()
+	} else if _, ok := s.messages[id]; ok {
+		return nil, errInput("a message with ID %s already exists", id)
+	}
+
+	createdAt, err := timeField(fields, "created_at")
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	if createdAt == nil {
+		createdAt = &now
+	}
+
+	m := &message{
+		id:        id,
+		cid:       ch.cid(),
+		userID:    userID,
+		parentID:  stringField(fields, "parent_id"),
+		typ:       stringField(fields, "type"),
+		text:      stringField(fields, "text"),
+		fields:    removeFields(fields, reservedMessageFields),
+		shadowed:  s.shadowBanned(userID, ch.cid()),
+		createdAt: *createdAt,
+		updatedAt: *createdAt,
+	}
+
+	if m.parentID != "" {
+		parent, ok := s.messages[m.parentID]
+		if !ok || parent.cid != m.cid {
+			return nil, errInput("parent message %q does not exist in the channel", m.parentID)
+		}
+		parent.replyCount++
+		if m.typ == "" {
+			m.typ = "reply"
+		}
+	}
+	if m.typ == "" {
+		m.typ = "regular"
+	}
+
+	s.ensureUser(userID)
+	s.messages[m.id] = m
+	ch.addMessage(m)
+	return m, nil
+}
+
+func (s *Server) sendMessage(r *request) (interface{}, error) {
+	ch, err := s.channel(r)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := objectField(r.body, "message")
+	if fields == nil {
+		return nil, errInput("message is a required field")
+	}
+	// the send time is set by the server
+	fields = removeFields(fields, []string{"created_at"})
+
+	m, err := s.newMessage(ch, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	// the sender of a shadowed message must not know it is shadowed
+	resp := s.messageJSON(m)
+	resp["shadowed"] = false
+	return object{"message": resp}, nil
+}
+
+func (s *Server) message(id string) (*message, error) {
+	m, ok := s.messages[id]
+	if !ok {
+		return nil, errNotFound("message %q does not exist", id)
+	}
+	return m, nil
+}
+
+func (s *Server) getMessage(r *request) (interface{}, error) {
+	m, err := s.message(r.param(0))
+	if err != nil {
+		return nil, err
+	}
+	return object{"message": s.messageJSON(m)}, nil
+}
+
+func (s *Server) updateMessage(r *request) (interface{}, error) {
+	m, err := s.message(r.param(0))
+	if err != nil {
+		return nil, err
+	}
+
+	fields := objectField(r.body, "message")
+	if fields == nil {
+		return nil, errInput("message is a required field")
+	}
+	if id := stringField(fields, "id"); id != "" && id != m.id {
+		return nil, errInput("message.id %q does not match the message to update", id)
+	}
+
+	m.text = stringField(fields, "text")
+	m.fields = removeFields(fields, reservedMessageFields)
+	m.updatedAt = s.now()
+	return object{"message": s.messageJSON(m)}, nil
+}
+
+func (s *Server) updateMessagePartial(r *request) (interface{}, error) {
+	m, err := s.message(r.param(0))
+	if err != nil {
+		return nil, err
+	}
+
+	set := objectField(r.body, "set")
+	if set == nil {
+		set = objectField(r.body, "Set")
+	}
+	unset, _ := r.body["unset"].([]interface{})
+	if unset == nil {
+		unset, _ = r.body["Unset"].([]interface{})
+	}
+
+	now := s.now()
+	fields := copyObject(m.fields)
+	for k, v := range set {
+		switch k {
+		case "text":
+			m.text, _ = v.(string)
+		case "pinned":
+			if pinned, _ := v.(bool); pinned {
+				expires, err := timeField(set, "pin_expires")
+				if err != nil {
+					return nil, err
+				}
+				m.pinnedAt = &now
+				m.pinnedBy = stringField(r.body, "user_id")
+				m.pinExpires = expires
+			} else {
+				m.pinnedAt, m.pinnedBy, m.pinExpires = nil, "", nil
+			}
+		case "pin_expires":
+		default:
+			if !isReserved(k, reservedMessageFields) {
+				setPath(fields, k, v)
+			}
+		}
+	}
+	for _, k := range unset {
+		if k, ok := k.(string); ok {
+			unsetPath(fields, k)
+		}
+	}
+	m.fields = fields
+	m.updatedAt = now
+	return object{"message": s.messageJSON(m)}, nil
+}
+
+func (s *Server) deleteMessage(r *request) (interface{}, error) {
+	m, err := s.message(r.param(0))
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	m.deletedAt = &now
+	m.typ = "deleted"
+	resp := object{"message": s.messageJSON(m)}
+
+	if r.URL.Query().Get("hard") == "true" {
+		delete(s.messages, m.id)
+		if ch, ok := s.channels[m.cid]; ok {
+			ch.removeMessage(m.id)
+		}
+		if parent, ok := s.messages[m.parentID]; ok {
+			parent.replyCount--
+		}
+	}
+	return resp, nil
+}
+
+func (s *Server) getReplies(r *request) (interface{}, error) {
+	parent, err := s.message(r.param(0))
+	if err != nil {
+		return nil, err
+	}
+
+	var replies []*message
+	for _, m := range s.channels[parent.cid].messages {
+		if m.parentID == parent.id {
+			replies = append(replies, m)
+		}
+	}
+
+	start, end := paginate(len(replies), queryObject(r), 100)
+	messages := make([]interface{}, 0, end-start)
+	for _, m := range replies[start:end] {
+		messages = append(messages, s.messageJSON(m))
+	}
+	return object{"messages": messages}, nil
+}
+
+func (s *Server) runMessageAction(r *request) (interface{}, error) {
+	m, err := s.message(r.param(0))
+	if err != nil {
+		return nil, err
+	}
+	if len(objectField(r.body, "form_data")) == 0 {
+		return nil, errInput("form_data is a required field")
+	}
+	return object{"message": s.messageJSON(m)}, nil
+}
+
+func (s *Server) sendReaction(r *request) (interface{}, error) {
+	m, err := s.message(r.param(0))
+	if err != nil {
+		return nil, err
+	}
+
+	fields := objectField(r.body, "reaction")
+	userID := stringField(fields, "user_id")
+	if userID == "" {
+		userID = idOf(fields["user"])
+	}
+	typ := stringField(fields, "type")
+	if userID == "" || typ == "" {
+		return nil, errInput("reaction.type and reaction.user_id are required fields")
+	}
+	s.ensureUser(userID)
+
+	if boolField(r.body, "enforce_unique") {
+		m.reactions = removeReactions(m.reactions, userID, "")
+	} else {
+		m.reactions = removeReactions(m.reactions, userID, typ)
+	}
+
+	now := s.now()
+	re := &reaction{
+		messageID: m.id,
+		userID:    userID,
+		typ:       typ,
+		fields:    removeFields(fields, []string{"message_id", "user_id", "user", "type", "score", "created_at", "updated_at"}),
+		createdAt: now,
+		updatedAt: now,
+	}
+	m.reactions = append(m.reactions, re)
+	m.updatedAt = now
+
+	return object{"message": s.messageJSON(m), "reaction": s.reactionJSON(re)}, nil
+}
+
+func (s *Server) deleteReaction(r *request) (interface{}, error) {
+	m, err := s.message(r.param(0))
+	if err != nil {
+		return nil, err
+	}
+
+	userID, typ := r.URL.Query().Get("user_id"), r.param(1)
+	var deleted *reaction
+	for _, re := range m.reactions {
+		if re.userID == userID && re.typ == typ {
+			deleted = re
+		}
+	}
+	if deleted == nil {
+		return nil, errNotFound("reaction %q of user %q does not exist", typ, userID)
+	}
+
+	m.reactions = removeReactions(m.reactions, userID, typ)
+	m.updatedAt = s.now()
+	return object{"message": s.messageJSON(m), "reaction": s.reactionJSON(deleted)}, nil
+}
+
+// removeReactions removes the reactions of the user, only the ones of the given type if not empty.
+func removeReactions(reactions []*reaction, userID, typ string) []*reaction {
+	kept := reactions[:0]
+	for _, re := range reactions {
+		if re.userID != userID || (typ != "" && re.typ != typ) {
+			kept = append(kept, re)
+		}
+	}
+	return kept
+}
+
+func (s *Server) getReactions(r *request) (interface{}, error) {
+	m, err := s.message(r.param(0))
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := paginate(len(m.reactions), queryObject(r), 10)
+	reactions := make([]interface{}, 0, end-start)
+	for i := len(m.reactions) - 1 - start; i >= len(m.reactions)-end; i-- {
+		reactions = append(reactions, s.reactionJSON(m.reactions[i]))
+	}
+	return object{"reactions": reactions}, nil
+}
+
+func (s *Server) search(r *request) (interface{}, error) {
+	q, err := r.payload()
+	if err != nil {
+		return nil, err
+	}
+
+	channelFilter := objectField(q, "filter_conditions")
+	messageFilter := objectField(q, "message_filter_conditions")
+	text := stringField(q, "query")
+	sortOption, _ := q["sort"].([]interface{})
+
+	switch {
+	case len(channelFilter) == 0:
+		return nil, errInput("filter_conditions is a required field")
+	case text == "" && len(messageFilter) == 0:
+		return nil, errInput("either query or message_filter_conditions must be provided")
+	case text != "" && len(messageFilter) > 0:
+		return nil, errInput("query and message_filter_conditions cannot be used together")
+	case intField(q, "offset") > 0 && (len(sortOption) > 0 || stringField(q, "next") != ""):
+		return nil, errInput("offset cannot be used with sort or next")
+	}
+
+	offset := intField(q, "offset")
+	if next := stringField(q, "next"); next != "" {
+		if offset, err = decodeCursor(next); err != nil {
+			return nil, err
+		}
+	}
+
+	var messages []*message
+	var docs []object
+	for _, ch := range s.channels {
+		doc := toDocument(s.channelJSON(ch))
+		doc["members"] = ch.memberIDs()
+		ok, err := match(doc, channelFilter)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		for _, m := range ch.messages {
+			if m.deletedAt != nil || (text != "" && !fullText(m.text, text)) {
+				continue
+			}
+			messages = append(messages, m)
+			docs = append(docs, toDocument(s.messageJSON(m)))
+		}
+	}
+
+	indexes, err := query(docs, messageFilter, q["sort"], object{"field": "created_at", "direction": 1})
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := paginate(len(indexes), object{"offset": offset, "limit": q["limit"]}, 20)
+	results := make([]interface{}, 0, end-start)
+	for _, i := range indexes[start:end] {
+		results = append(results, object{"message": s.messageJSON(messages[i])})
+	}
+
+	resp := object{"results": results}
+	if end < len(indexes) {
+		resp["next"] = encodeCursor(end)
+	}
+	if start > 0 {
+		resp["previous"] = encodeCursor(start - (end - start))
+	}
+	return resp, nil
+}
+
+// queryObject returns the limit and offset query parameters of GET requests.
+func queryObject(r *request) object {
+	params := r.URL.Query()
+	q := object{}
+	for _, k := range []string{"limit", "offset"} {
+		if n, err := strconv.Atoi(params.Get(k)); err == nil {
+			q[k] = n
+		}
+	}
+	return q
+}
+
+func encodeCursor(offset int) string {
+	if offset < 0 {
+		offset = 0
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		var offset int
+		if offset, err = strconv.Atoi(string(b)); err == nil {
+			return offset, nil
+		}
+	}
+	return 0, errInput("next is not a valid cursor")
+}
@@ -0,0 +1,273 @@
+package streamtest
+
+import "time"
+
+func (s *Server) muteUsers(r *request) (interface{}, error) {
+	userID := stringField(r.body, "user_id")
+	targets := stringList(r.body, "target_ids")
+	if id := stringField(r.body, "target_id"); id != "" {
+		targets = append(targets, id)
+	}
+	if userID == "" || len(targets) == 0 {
+		return nil, errInput("user_id and target_id or target_ids are required fields")
+	}
+	if err := s.existingUsers(append([]string{userID}, targets...), "the mute"); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	mutes := make([]interface{}, 0, len(targets))
+	for _, target := range targets {
+		s.removeMute(userID, target)
+		m := &mute{
+			userID:    userID,
+			targetID:  target,
+			expires:   minutes(now, r.body, "timeout"),
+			createdAt: now,
+			updatedAt: now,
+		}
+		s.mutes = append(s.mutes, m)
+		mutes = append(mutes, s.muteJSON(m))
+	}
+
+	resp := object{"own_user": s.fullUserJSON(userID)}
+	if len(mutes) == 1 {
+		resp["mute"] = mutes[0]
+	} else {
+		resp["mutes"] = mutes
+	}
+	return resp, nil
+}
+
+func (s *Server) unmuteUsers(r *request) (interface{}, error) {
+	userID := stringField(r.body, "user_id")
+	targets := stringList(r.body, "target_ids")
+	if id := stringField(r.body, "target_id"); id != "" {
+		targets = append(targets, id)
+	}
+	if userID == "" || len(targets) == 0 {
+		return nil, errInput("user_id and target_id or target_ids are required fields")
+	}
+
+	for _, target := range targets {
+		s.removeMute(userID, target)
+	}
+	return nil, nil
+}
+
+func (s *Server) removeMute(userID, targetID string) {
+	for i, m := range s.mutes {
+		if m.userID == userID && m.targetID == targetID {
+			s.mutes = append(s.mutes[:i], s.mutes[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *Server) muteChannel(r *request) (interface{}, error) {
+	userID := stringField(r.body, "user_id")
+	cid := stringField(r.body, "channel_cid")
+	if _, ok := s.users[userID]; !ok {
+		return nil, errInput("user %q does not exist", userID)
+	}
+	if _, ok := s.channels[cid]; !ok {
+		return nil, errNotFound("channel %q does not exist", cid)
+	}
+
+	now := s.now()
+	m := &channelMute{userID: userID, cid: cid, createdAt: now, updatedAt: now}
+	if ms := intField(r.body, "expiration"); ms > 0 {
+		t := now.Add(time.Duration(ms) * time.Millisecond)
+		m.expires = &t
+	}
+	s.removeChannelMute(userID, cid)
+	s.channelMutes = append(s.channelMutes, m)
+
+	return object{"channel_mute": s.channelMuteJSON(m), "own_user": s.fullUserJSON(userID)}, nil
+}
+
+func (s *Server) unmuteChannel(r *request) (interface{}, error) {
+	s.removeChannelMute(stringField(r.body, "user_id"), stringField(r.body, "channel_cid"))
+	return nil, nil
+}
+
+func (s *Server) removeChannelMute(userID, cid string) {
+	for i, m := range s.channelMutes {
+		if m.userID == userID && m.cid == cid {
+			s.channelMutes = append(s.channelMutes[:i], s.channelMutes[i+1:]...)
+			return
+		}
+	}
+}
+
+// channelMuted reports whether the user muted the channel.
+func (s *Server) channelMuted(userID, cid string) bool {
+	for _, m := range s.channelMutes {
+		if m.userID == userID && m.cid == cid {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) flag(r *request) (interface{}, error) {
+	f, err := s.flagTarget(r)
+	if err != nil {
+		return nil, err
+	}
+
+	s.removeFlag(f)
+	f.createdAt = s.now()
+	s.flags = append(s.flags, f)
+	return object{"flag": s.flagJSON(f)}, nil
+}
+
+func (s *Server) unflag(r *request) (interface{}, error) {
+	f, err := s.flagTarget(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.removeFlag(f) {
+		return nil, errNotFound("flag does not exist")
+	}
+	return object{"flag": s.flagJSON(f)}, nil
+}
+
+// flagTarget returns the flag described by the request, without its creation time.
+func (s *Server) flagTarget(r *request) (*flag, error) {
+	f := &flag{
+		userID:          stringField(r.body, "user_id"),
+		targetMessageID: stringField(r.body, "target_message_id"),
+		targetUserID:    stringField(r.body, "target_user_id"),
+	}
+	if f.userID == "" {
+		return nil, errInput("user_id is a required field")
+	}
+
+	switch {
+	case f.targetMessageID != "":
+		if _, ok := s.messages[f.targetMessageID]; !ok {
+			return nil, errNotFound("message %q does not exist", f.targetMessageID)
+		}
+	case f.targetUserID != "":
+		if _, ok := s.users[f.targetUserID]; !ok {
+			return nil, errNotFound("user %q does not exist", f.targetUserID)
+		}
+	default:
+		return nil, errInput("either target_message_id or target_user_id must be provided")
+	}
+	return f, nil
+}
+
+func (s *Server) removeFlag(f *flag) bool {
+	for i, old := range s.flags {
+		if old.userID == f.userID && old.targetMessageID == f.targetMessageID && old.targetUserID == f.targetUserID {
+			s.flags = append(s.flags[:i], s.flags[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) queryMessageFlags(r *request) (interface{}, error) {
+	q, err := r.payload()
+	if err != nil {
+		return nil, err
+	}
+
+	var flags []*flag
+	var docs []object
+	for _, f := range s.flags {
+		m, ok := s.messages[f.targetMessageID]
+		if !ok {
+			continue
+		}
+		flags = append(flags, f)
+		docs = append(docs, object{
+			"channel_cid":     m.cid,
+			"user_id":         f.userID,
+			"message_id":      m.id,
+			"message_user_id": m.userID,
+			"created_at":      f.createdAt.Format(time.RFC3339Nano),
+		})
+	}
+
+	indexes, err := query(docs, objectField(q, "filter_conditions"), nil,
+		object{"field": "created_at", "direction": -1})
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := paginate(len(indexes), q, 100)
+	resp := make([]interface{}, 0, end-start)
+	for _, i := range indexes[start:end] {
+		resp = append(resp, s.flagJSON(flags[i]))
+	}
+	return object{"flags": resp}, nil
+}
+
+func (s *Server) ban(r *request) (interface{}, error) {
+	b := &ban{
+		targetID: stringField(r.body, "target_user_id"),
+		bannedBy: stringField(r.body, "user_id"),
+		shadow:   boolField(r.body, "shadow"),
+		reason:   stringField(r.body, "reason"),
+	}
+	if b.targetID == "" || b.bannedBy == "" {
+		return nil, errInput("target_user_id and user_id are required fields")
+	}
+	if err := s.existingUsers([]string{b.targetID, b.bannedBy}, "the ban"); err != nil {
+		return nil, err
+	}
+
+	if typ, id := stringField(r.body, "type"), stringField(r.body, "id"); typ != "" || id != "" {
+		b.cid = typ + ":" + id
+		if _, ok := s.channels[b.cid]; !ok {
+			return nil, errNotFound("channel %q does not exist", b.cid)
+		}
+	}
+
+	now := s.now()
+	b.createdAt = now
+	b.expires = minutes(now, r.body, "timeout")
+
+	s.removeBans(b.targetID, b.cid, b.shadow)
+	s.bans = append(s.bans, b)
+	return nil, nil
+}
+
+func (s *Server) unban(r *request) (interface{}, error) {
+	params := r.URL.Query()
+	target := params.Get("target_user_id")
+	if target == "" {
+		return nil, errInput("target_user_id is a required field")
+	}
+
+	var cid string
+	if typ, id := params.Get("type"), params.Get("id"); typ != "" || id != "" {
+		cid = typ + ":" + id
+	}
+	s.removeBans(target, cid, params.Get("shadow") == "true")
+	return nil, nil
+}
+
+func (s *Server) removeBans(targetID, cid string, shadow bool) {
+	bans := s.bans[:0]
+	for _, b := range s.bans {
+		if b.targetID != targetID || b.cid != cid || b.shadow != shadow {
+			bans = append(bans, b)
+		}
+	}
+	s.bans = bans
+}
+
+// shadowBanned reports whether the messages of the user in the channel are shadowed.
+func (s *Server) shadowBanned(userID, cid string) bool {
+	for _, b := range s.bans {
+		if b.shadow && b.targetID == userID && (b.cid == "" || b.cid == cid) && b.active() {
+			return true
+		}
+	}
+	return false
+}
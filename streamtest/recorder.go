@@ -0,0 +1,412 @@
+package streamtest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Mode selects whether a Recorder sends the requests to the API or replays a cassette.
+type Mode int
+
+const (
+	// ModeReplay serves the responses recorded in the cassette, without sending any request.
+	ModeReplay Mode = iota
+	// ModeRecord sends the requests to the API and records them, replacing the cassette.
+	ModeRecord
+	// ModeAuto replays the cassette if it exists and records it otherwise.
+	ModeAuto
+)
+
+// redacted replaces the secrets in the cassettes.
+const redacted = "REDACTED"
+
+// sensitiveFields are the JSON fields redacted from the request and response bodies.
+//nolint: gochecknoglobals
+var sensitiveFields = []string{"api_key", "api_secret", "secret", "token", "auth_key", "server_key", "p12_cert"}
+
+// CassettePath returns the path of the cassette with the given name, in the testdata directory of
+// the package being tested.
+func CassettePath(name string) string {
+	return filepath.Join("testdata", "cassettes", name+".json")
+}
+
+// VolatileValues returns the values ignored by default when a Recorder matches requests: the
+// random IDs made of upper case letters generated by the tests, UUIDs and RFC 3339 timestamps.
+func VolatileValues() []*regexp.Regexp {
+	return []*regexp.Regexp{
+		regexp.MustCompile(`\b[A-Z]{5,}\b`),
+		regexp.MustCompile(`\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`),
+		regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:\d{2})`),
+	}
+}
+
+// Recorder is an http.RoundTripper recording the requests sent to the API and their responses in a
+// cassette file, to replay them later without network access or credentials. It is used as the
+// transport of the HTTP client of the stream_chat client:
+//
+//	rec, err := streamtest.NewRecorder(streamtest.CassettePath("users"), streamtest.ModeAuto)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer rec.Stop()
+//
+//	client, err := stream_chat.NewClient(apiKey, apiSecret,
+//		stream_chat.WithHTTPClient(&http.Client{Transport: rec}))
+//
+// The api_key parameter, the Authorization header, the sensitive fields of the bodies like the
+// push credentials and the values listed in Secrets are redacted from the cassette.
+//
+// In replay mode, a request is served the response of the first request of the cassette not
+// replayed yet with the same method, URL and body, ignoring the values matched by Volatile. The
+// volatile values of the recorded request are replaced in the response by the ones of the request
+// being replayed, so that a test generating random IDs finds its own IDs in the responses.
+type Recorder struct {
+	// Transport sends the requests in record mode, http.DefaultTransport if nil.
+	Transport http.RoundTripper
+	// Secrets lists values to redact from the cassette, like the API secret.
+	Secrets []string
+	// Volatile matches the values ignored when matching requests, VolatileValues() if nil.
+	Volatile []*regexp.Regexp
+
+	path string
+	mode Mode
+
+	mu           sync.Mutex
+	interactions []*interaction
+	replayed     []bool
+	volatile     *regexp.Regexp
+}
+
+// cassette is the content of a cassette file.
+type cassette struct {
+	Interactions []*interaction `json:"interactions"`
+}
+
+type interaction struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+}
+
+type recordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type recordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// NewRecorder returns a Recorder using the cassette file at path. In replay mode, the cassette
+// must exist. In record mode, it is written by Stop.
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	if mode == ModeAuto {
+		mode = ModeRecord
+		if _, err := os.Stat(path); err == nil {
+			mode = ModeReplay
+		}
+	}
+
+	r := &Recorder{path: path, mode: mode}
+	if mode != ModeReplay {
+		return r, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read cassette: %w", err)
+	}
+	var c cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("cannot decode cassette %s: %w", path, err)
+	}
+	r.interactions = c.Interactions
+	r.replayed = make([]bool, len(c.Interactions))
+	return r, nil
+}
+
+// Recording reports whether the requests are sent to the API and recorded.
+func (r *Recorder) Recording() bool {
+	return r.mode == ModeRecord
+}
+
+// Stop writes the cassette in record mode. It does nothing in replay mode.
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	data, err := json.MarshalIndent(cassette{Interactions: r.interactions}, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, append(data, '\n'), 0o600)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	recorded := recordedRequest{
+		Method: req.Method,
+		URL:    r.redactURL(req),
+		Header: r.redactHeader(req.Header),
+		Body:   r.redactBody(gunzip(req.Header, body), req.Header.Get("Content-Type")),
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+	return r.record(req, body, recorded)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte, recorded recordedRequest) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	// the cassettes and the caller get the decompressed body, so that it can be replayed as is
+	if isGzipped(resp.Header) {
+		if respBody, err = gunzipBytes(respBody); err != nil {
+			return nil, err
+		}
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Set("Content-Length", strconv.Itoa(len(respBody)))
+		resp.ContentLength = int64(len(respBody))
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, &interaction{
+		Request: recorded,
+		Response: recordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     r.redactHeader(resp.Header),
+			Body:       r.redactBody(respBody, resp.Header.Get("Content-Type")),
+		},
+	})
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, live recordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	volatile := r.volatileValues()
+	key := live.matchKey(volatile)
+	for i, in := range r.interactions {
+		if r.replayed[i] || in.Request.matchKey(volatile) != key {
+			continue
+		}
+		r.replayed[i] = true
+
+		body := in.Response.Body
+		if replacer := substitutions(volatile, in.Request, live); replacer != nil {
+			body = replacer.Replace(body)
+		}
+		header := in.Response.Header.Clone()
+		header.Set("Content-Length", strconv.Itoa(len(body)))
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(strings.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("no request recorded in %s matches %s %s", r.path, live.Method, live.URL)
+}
+
+func isGzipped(h http.Header) bool {
+	return strings.EqualFold(h.Get("Content-Encoding"), "gzip")
+}
+
+func gunzipBytes(b []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(zr)
+}
+
+// gunzip returns the decompressed request body if it is gzip encoded and valid, as it is otherwise.
+func gunzip(h http.Header, body []byte) []byte {
+	if !isGzipped(h) {
+		return body
+	}
+	if b, err := gunzipBytes(body); err == nil {
+		return b
+	}
+	return body
+}
+
+// volatileValues returns a single expression matching all the volatile values, in order of appearance.
+func (r *Recorder) volatileValues() *regexp.Regexp {
+	if r.volatile == nil {
+		exprs := r.Volatile
+		if exprs == nil {
+			exprs = VolatileValues()
+		}
+		alternatives := make([]string, len(exprs))
+		for i, e := range exprs {
+			alternatives[i] = "(?:" + e.String() + ")"
+		}
+		r.volatile = regexp.MustCompile(strings.Join(alternatives, "|"))
+	}
+	return r.volatile
+}
+
+// matchKey returns the representation of the request compared in replay mode.
+func (req recordedRequest) matchKey(volatile *regexp.Regexp) string {
+	return volatile.ReplaceAllString(req.Method+" "+req.URL+"\n"+req.Body, "{volatile}")
+}
+
+// substitutions returns the replacer of the volatile values of the recorded request by the ones
+// of the live request, or nil if they are the same.
+func substitutions(volatile *regexp.Regexp, recorded, live recordedRequest) *strings.Replacer {
+	old := volatile.FindAllString(recorded.URL+"\n"+recorded.Body, -1)
+	cur := volatile.FindAllString(live.URL+"\n"+live.Body, -1)
+	if len(old) != len(cur) {
+		return nil
+	}
+
+	var pairs []string
+	seen := make(map[string]bool)
+	for i := range old {
+		if old[i] != cur[i] && !seen[old[i]] {
+			seen[old[i]] = true
+			pairs = append(pairs, old[i], cur[i])
+		}
+	}
+	if len(pairs) == 0 {
+		return nil
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+func (r *Recorder) redactURL(req *http.Request) string {
+	u := *req.URL
+	q := u.Query()
+	if q.Get("api_key") != "" {
+		q.Set("api_key", redacted)
+	}
+	u.RawQuery = q.Encode()
+	return r.redactSecrets(u.RequestURI())
+}
+
+func (r *Recorder) redactHeader(h http.Header) http.Header {
+	out := make(http.Header, len(h))
+	for k, values := range h {
+		if k == "Authorization" {
+			out[k] = []string{redacted}
+			continue
+		}
+		for _, v := range values {
+			out[k] = append(out[k], r.redactSecrets(v))
+		}
+	}
+	return out
+}
+
+// redactBody returns the body as a string, with the sensitive fields of JSON bodies redacted.
+// The random boundary of multipart bodies is replaced by a constant so that they can be matched.
+func (r *Recorder) redactBody(body []byte, contentType string) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	s := string(body)
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/json":
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			break
+		}
+		redactJSON(v)
+
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(v); err == nil {
+			s = strings.TrimSuffix(buf.String(), "\n")
+		}
+	case params["boundary"] != "":
+		s = strings.ReplaceAll(s, params["boundary"], "BOUNDARY")
+	}
+	return r.redactSecrets(strings.ToValidUTF8(s, "�"))
+}
+
+func (r *Recorder) redactSecrets(s string) string {
+	for _, secret := range r.Secrets {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, redacted)
+		}
+	}
+	return s
+}
+
+func redactJSON(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, field := range v {
+			if field != nil && isReserved(k, sensitiveFields) {
+				v[k] = redacted
+				continue
+			}
+			redactJSON(field)
+		}
+	case []interface{}:
+		for _, item := range v {
+			redactJSON(item)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package streamtest_test
+
+import (
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	stream "github.com/GetStream/stream-chat-go/v4"
+	"github.com/GetStream/stream-chat-go/v4/streamtest"
+)
+
+func TestRecorder(t *testing.T) {
+	ctx := context.Background()
+
+	dir, err := ioutil.TempDir("", "cassettes")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "testdata", "recorder.json")
+
+	srv := streamtest.NewServer("recorded-key", "recorded-secret")
+	defer srv.Close()
+
+	rec, err := streamtest.NewRecorder(path, streamtest.ModeAuto)
+	require.NoError(t, err)
+	require.True(t, rec.Recording())
+	rec.Secrets = []string{srv.APISecret}
+
+	c, err := stream.NewClient(srv.APIKey, srv.APISecret,
+		stream.WithBaseURL(srv.URL), stream.WithHTTPClient(&http.Client{Transport: rec}))
+	require.NoError(t, err)
+
+	settings := stream.NewAppSettings().SetFirebaseConfig(stream.FirebaseConfig{ServerKey: "firebase-server-key"})
+	require.NoError(t, c.UpdateAppSettings(ctx, settings))
+	u, err := c.UpsertUser(ctx, &stream.User{ID: "RECORDEDID", Name: "recorded"})
+	require.NoError(t, err)
+	require.Equal(t, "RECORDEDID", u.ID)
+	require.NoError(t, rec.Stop())
+
+	t.Run("secrets are redacted", func(t *testing.T) {
+		data, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		for _, secret := range []string{srv.APIKey, srv.APISecret, "firebase-server-key", "Bearer", "eyJ"} {
+			require.NotContains(t, string(data), secret)
+		}
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		rec, err := streamtest.NewRecorder(path, streamtest.ModeAuto)
+		require.NoError(t, err)
+		require.False(t, rec.Recording())
+
+		c, err := stream.NewClient("key", "secret",
+			stream.WithBaseURL("http://localhost:1"), stream.WithHTTPClient(&http.Client{Transport: rec}))
+		require.NoError(t, err)
+
+		require.NoError(t, c.UpdateAppSettings(ctx, settings))
+		u, err := c.UpsertUser(ctx, &stream.User{ID: "REPLAYEDID", Name: "recorded"})
+		require.NoError(t, err)
+		require.Equal(t, "REPLAYEDID", u.ID, "volatile IDs are replaced in the responses")
+
+		_, err = c.UpsertUser(ctx, &stream.User{ID: "REPLAYEDID", Name: "recorded"})
+		require.Error(t, err, "every recorded request is replayed once")
+		_, err = c.UpsertUser(ctx, &stream.User{ID: "ANOTHERID", Name: "not recorded"})
+		require.Error(t, err)
+	})
+}
+
+func TestRecorder_Gzip(t *testing.T) {
+	ctx := context.Background()
+
+	dir, err := ioutil.TempDir("", "cassettes")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "gzip.json")
+
+	// the fake does not compress its responses, the API does
+	srv := streamtest.NewServer("key", "secret")
+	defer srv.Close()
+	gzipped := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, r)
+		for k, v := range rec.Header() {
+			w.Header()[k] = v
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(rec.Code)
+		zw := gzip.NewWriter(w)
+		_, _ = zw.Write(rec.Body.Bytes())
+		_ = zw.Close()
+	}))
+	defer gzipped.Close()
+
+	rec, err := streamtest.NewRecorder(path, streamtest.ModeRecord)
+	require.NoError(t, err)
+	c, err := stream.NewClient("key", "secret", stream.WithCompression(0),
+		stream.WithBaseURL(gzipped.URL), stream.WithHTTPClient(&http.Client{Transport: rec}))
+	require.NoError(t, err)
+
+	u, err := c.UpsertUser(ctx, &stream.User{ID: "RECORDEDID", Name: "gzipped"})
+	require.NoError(t, err)
+	require.Equal(t, "gzipped", u.Name)
+	require.NoError(t, rec.Stop())
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `\"name\":\"gzipped\"`, "the request body is recorded decompressed")
+	require.Contains(t, string(data), `"duration`, "the response body is recorded decompressed")
+
+	rec, err = streamtest.NewRecorder(path, streamtest.ModeReplay)
+	require.NoError(t, err)
+	c, err = stream.NewClient("key", "secret", stream.WithCompression(0),
+		stream.WithBaseURL(gzipped.URL), stream.WithHTTPClient(&http.Client{Transport: rec}))
+	require.NoError(t, err)
+
+	u, err = c.UpsertUser(ctx, &stream.User{ID: "REPLAYEDID", Name: "gzipped"})
+	require.NoError(t, err)
+	require.Equal(t, "REPLAYEDID", u.ID)
+}
@@ -0,0 +1,188 @@
+package streamtest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultRateLimit is the number of calls per minute allowed on every endpoint.
+const defaultRateLimit = 10000
+
+type handlerFunc func(s *Server, r *request) (interface{}, error)
+
+// route maps a request method and path pattern to its handler. A "*" in the pattern matches any
+// single path segment. name is the name the API uses for the endpoint in the rate limits.
+type route struct {
+	method  string
+	pattern string
+	name    string
+	handler handlerFunc
+}
+
+//nolint: gochecknoglobals
+var routes = []route{
+	{http.MethodGet, "app", "GetApp", (*Server).getApp},
+	{http.MethodPatch, "app", "UpdateApp", (*Server).updateApp},
+	{http.MethodGet, "rate_limits", "GetRateLimits", (*Server).getRateLimits},
+
+	{http.MethodGet, "tasks/*", "GetTask", (*Server).getTask},
+	{http.MethodPost, "export_channels", "ExportChannels", (*Server).exportChannels},
+	{http.MethodGet, "export_channels/*", "GetExportChannelsStatus", (*Server).getTask},
+
+	{http.MethodPost, "channels", "QueryChannels", (*Server).queryChannels},
+	{http.MethodPost, "channels/read", "MarkChannelsRead", (*Server).markAllRead},
+	{http.MethodPost, "channels/delete", "DeleteChannels", (*Server).deleteChannels},
+	{http.MethodPost, "channels/*/query", "GetOrCreateChannel", (*Server).getOrCreateChannel},
+	{http.MethodPost, "channels/*/*/query", "GetOrCreateChannel", (*Server).getOrCreateChannel},
+	{http.MethodPost, "channels/*/*", "UpdateChannel", (*Server).updateChannel},
+	{http.MethodPatch, "channels/*/*", "UpdateChannelPartial", (*Server).updateChannelPartial},
+	{http.MethodDelete, "channels/*/*", "DeleteChannel", (*Server).deleteChannel},
+	{http.MethodPost, "channels/*/*/truncate", "TruncateChannel", (*Server).truncateChannel},
+	{http.MethodPost, "channels/*/*/import", "ImportChannelMessages", (*Server).importMessages},
+	{http.MethodPost, "channels/*/*/read", "MarkRead", (*Server).markRead},
+	{http.MethodPost, "channels/*/*/show", "ShowChannel", (*Server).showChannel},
+	{http.MethodPost, "channels/*/*/hide", "HideChannel", (*Server).hideChannel},
+	{http.MethodPost, "channels/*/*/event", "SendEvent", (*Server).sendEvent},
+	{http.MethodPost, "channels/*/*/message", "SendMessage", (*Server).sendMessage},
+	{http.MethodPost, "channels/*/*/file", "UploadFile", (*Server).uploadFile},
+	{http.MethodDelete, "channels/*/*/file", "DeleteFile", (*Server).deleteFile},
+	{http.MethodPost, "channels/*/*/image", "UploadImage", (*Server).uploadFile},
+	{http.MethodDelete, "channels/*/*/image", "DeleteImage", (*Server).deleteFile},
+	{http.MethodGet, "members", "QueryMembers", (*Server).queryMembers},
+
+	{http.MethodGet, "messages/*", "GetMessage", (*Server).getMessage},
+	{http.MethodPost, "messages/*", "UpdateMessage", (*Server).updateMessage},
+	{http.MethodPut, "messages/*", "UpdateMessagePartial", (*Server).updateMessagePartial},
+	{http.MethodDelete, "messages/*", "DeleteMessage", (*Server).deleteMessage},
+	{http.MethodGet, "messages/*/replies", "GetReplies", (*Server).getReplies},
+	{http.MethodPost, "messages/*/action", "RunMessageAction", (*Server).runMessageAction},
+	{http.MethodPost, "messages/*/reaction", "SendReaction", (*Server).sendReaction},
+	{http.MethodDelete, "messages/*/reaction/*", "DeleteReaction", (*Server).deleteReaction},
+	{http.MethodGet, "messages/*/reactions", "GetReactions", (*Server).getReactions},
+	{http.MethodGet, "search", "Search", (*Server).search},
+
+	{http.MethodGet, "users", "QueryUsers", (*Server).queryUsers},
+	{http.MethodPost, "users", "UpdateUsers", (*Server).upsertUsers},
+	{http.MethodPatch, "users", "UpdateUsersPartial", (*Server).updateUsersPartial},
+	{http.MethodPost, "users/delete", "DeleteUsers", (*Server).deleteUsers},
+	{http.MethodDelete, "users/*", "DeleteUser", (*Server).deleteUser},
+	{http.MethodGet, "users/*/export", "ExportUser", (*Server).exportUser},
+	{http.MethodPost, "users/*/deactivate", "DeactivateUser", (*Server).deactivateUser},
+	{http.MethodPost, "users/*/reactivate", "ReactivateUser", (*Server).reactivateUser},
+	{http.MethodPost, "users/*/event", "SendUserCustomEvent", (*Server).sendUserCustomEvent},
+
+	{http.MethodPost, "moderation/mute", "MuteUser", (*Server).muteUsers},
+	{http.MethodPost, "moderation/unmute", "UnmuteUser", (*Server).unmuteUsers},
+	{http.MethodPost, "moderation/mute/channel", "MuteChannel", (*Server).muteChannel},
+	{http.MethodPost, "moderation/unmute/channel", "UnmuteChannel", (*Server).unmuteChannel},
+	{http.MethodPost, "moderation/flag", "Flag", (*Server).flag},
+	{http.MethodPost, "moderation/unflag", "Unflag", (*Server).unflag},
+	{http.MethodPost, "moderation/ban", "Ban", (*Server).ban},
+	{http.MethodDelete, "moderation/ban", "Unban", (*Server).unban},
+	{http.MethodGet, "moderation/flags/message", "QueryMessageFlags", (*Server).queryMessageFlags},
+
+	{http.MethodGet, "channeltypes", "ListChannelTypes", (*Server).listChannelTypes},
+	{http.MethodPost, "channeltypes", "CreateChannelType", (*Server).createChannelType},
+	{http.MethodGet, "channeltypes/*", "GetChannelType", (*Server).getChannelType},
+	{http.MethodPut, "channeltypes/*", "UpdateChannelType", (*Server).updateChannelType},
+	{http.MethodDelete, "channeltypes/*", "DeleteChannelType", (*Server).deleteChannelType},
+
+	{http.MethodGet, "commands", "ListCommands", (*Server).listCommands},
+	{http.MethodPost, "commands", "CreateCommand", (*Server).createCommand},
+	{http.MethodGet, "commands/*", "GetCommand", (*Server).getCommand},
+	{http.MethodPut, "commands/*", "UpdateCommand", (*Server).updateCommand},
+	{http.MethodDelete, "commands/*", "DeleteCommand", (*Server).deleteCommand},
+
+	{http.MethodGet, "devices", "ListDevices", (*Server).listDevices},
+	{http.MethodPost, "devices", "CreateDevice", (*Server).createDevice},
+	{http.MethodDelete, "devices", "DeleteDevice", (*Server).deleteDevice},
+}
+
+// findRoute returns the route serving the request and the path segments matched by its wildcards.
+func findRoute(method, path string) (*route, []string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	for i := range routes {
+		if routes[i].method != method {
+			continue
+		}
+		if params, ok := matchPattern(routes[i].pattern, segments); ok {
+			return &routes[i], params
+		}
+	}
+	return nil, nil
+}
+
+func matchPattern(pattern string, segments []string) ([]string, bool) {
+	parts := strings.Split(pattern, "/")
+	if len(parts) != len(segments) {
+		return nil, false
+	}
+
+	var params []string
+	for i, p := range parts {
+		switch {
+		case p == "*":
+			params = append(params, segments[i])
+		case p != segments[i]:
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// endpointNames returns the names of all the endpoints, in the order of the routes.
+func endpointNames() []string {
+	seen := make(map[string]bool, len(routes))
+	names := make([]string, 0, len(routes))
+	for _, r := range routes {
+		if !seen[r.name] {
+			seen[r.name] = true
+			names = append(names, r.name)
+		}
+	}
+	return names
+}
+
+// rateLimitWindow counts the calls made to an endpoint during a minute.
+type rateLimitWindow struct {
+	used  int64
+	reset time.Time
+}
+
+// takeRateLimit counts a call to the endpoint, sets the rate limit headers of the response
+// and fails once the quota of the current window is exhausted.
+func (s *Server) takeRateLimit(w http.ResponseWriter, endpoint string) error {
+	rl := s.rateLimit(endpoint)
+	rl.used++
+
+	remaining := defaultRateLimit - rl.used
+	if remaining < 0 {
+		remaining = 0
+	}
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(defaultRateLimit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+	w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(rl.reset.Unix(), 10))
+
+	if rl.used > defaultRateLimit {
+		return &apiError{
+			StatusCode: http.StatusTooManyRequests,
+			Code:       codeRateLimit,
+			Message:    "Too many requests, check response headers for more information.",
+		}
+	}
+	return nil
+}
+
+// rateLimit returns the current window of the endpoint.
+func (s *Server) rateLimit(endpoint string) *rateLimitWindow {
+	now := time.Now()
+	rl, ok := s.rateLimits[endpoint]
+	if !ok || !now.Before(rl.reset) {
+		rl = &rateLimitWindow{reset: now.Truncate(time.Minute).Add(time.Minute)}
+		s.rateLimits[endpoint] = rl
+	}
+	return rl
+}
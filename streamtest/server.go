@@ -0,0 +1,272 @@
+// Package streamtest provides an in-memory fake of the Stream Chat REST API, to test code using
+// the stream_chat client without network access or credentials.
+//
+// The fake keeps users, channels, messages, reactions, moderation data, channel types, commands,
+// devices and async tasks in memory, and authenticates requests like the real API, checking the
+// api_key parameter and the JWT signed with the API secret:
+//
+//	srv := streamtest.NewServer("key", "secret")
+//	defer srv.Close()
+//
+//	client, err := stream_chat.NewClient("key", "secret", stream_chat.WithBaseURL(srv.URL))
+//
+// It mimics the behavior of the API for the calls made by the client, but does not aim to be a
+// complete implementation: permissions, push notifications and websocket events are ignored.
+//
+// To test against the real API without credentials in CI, the Recorder transport records the
+// requests and responses in cassette files once and replays them afterwards.
+package streamtest
+
+import (
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// object is the generic representation of the JSON objects exchanged with the client.
+type object = map[string]interface{}
+
+// Server is a fake Stream Chat API served by an httptest.Server.
+type Server struct {
+	*httptest.Server
+
+	// APIKey and APISecret are the credentials accepted by the server.
+	APIKey    string
+	APISecret string
+
+	// endpoints lists the names of the endpoints reported in the rate limits.
+	endpoints []string
+
+	mu    sync.Mutex
+	clock time.Time
+	*state
+}
+
+// NewServer starts a Server accepting requests made with the given credentials.
+// The caller should call Close when finished, to shut it down.
+func NewServer(apiKey, apiSecret string) *Server {
+	s := &Server{APIKey: apiKey, APISecret: apiSecret, endpoints: endpointNames()}
+	s.state = newState(s.now())
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// Reset drops all the data stored by the server, restoring the default channel types and commands.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state = newState(s.now())
+}
+
+// now returns the current time, always later than the previous call so that objects created
+// in a row have distinct and ordered timestamps.
+func (s *Server) now() time.Time {
+	t := time.Now().UTC().Truncate(time.Microsecond)
+	if !t.After(s.clock) {
+		t = s.clock.Add(time.Microsecond)
+	}
+	s.clock = t
+	return t
+}
+
+// apiError is the error response of the API, decoded by the client into an APIError.
+type apiError struct {
+	StatusCode int    `json:"StatusCode"`
+	Code       int    `json:"code"`
+	Message    string `json:"message"`
+	Duration   string `json:"duration"`
+}
+
+func (e *apiError) Error() string {
+	return e.Message
+}
+
+// Error codes of the API, see https://getstream.io/chat/docs/go-golang/api_errors_response/.
+const (
+	codeAccessKey             = 2
+	codeAuthenticationFailed  = 3
+	codeInput                 = 4
+	codeRateLimit             = 9
+	codeDoesNotExist          = 16
+	codeTokenExpired          = 40
+	codeTokenSignatureInvalid = 43
+)
+
+func errInput(format string, args ...interface{}) error {
+	return &apiError{StatusCode: http.StatusBadRequest, Code: codeInput, Message: fmt.Sprintf(format, args...)}
+}
+
+func errNotFound(format string, args ...interface{}) error {
+	return &apiError{StatusCode: http.StatusNotFound, Code: codeDoesNotExist, Message: fmt.Sprintf(format, args...)}
+}
+
+func errUnauthorized(code int, format string, args ...interface{}) error {
+	return &apiError{StatusCode: http.StatusUnauthorized, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// request is an API call being served.
+type request struct {
+	*http.Request
+
+	// params holds the path segments matched by the wildcards of the route.
+	params []string
+	// body is the decoded JSON body, empty for other requests.
+	body object
+}
+
+func (r *request) param(i int) string {
+	return r.params[i]
+}
+
+// payload decodes the JSON object passed in the payload query parameter by GET queries.
+func (r *request) payload() (object, error) {
+	p := object{}
+	if v := r.URL.Query().Get("payload"); v != "" {
+		if err := json.Unmarshal([]byte(v), &p); err != nil {
+			return nil, errInput("payload is not valid JSON: %v", err)
+		}
+	}
+	return p, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := time.Now()
+	w.Header().Set("X-Request-Id", newID())
+
+	resp, err := s.serve(w, r)
+	if err != nil {
+		var apiErr *apiError
+		if !errors.As(err, &apiErr) {
+			apiErr = &apiError{StatusCode: http.StatusInternalServerError, Code: -1, Message: err.Error()}
+		}
+		apiErr.Duration = formatDuration(time.Since(start))
+		writeJSON(w, apiErr.StatusCode, apiErr)
+		return
+	}
+
+	if m, ok := resp.(object); ok {
+		m["duration"] = formatDuration(time.Since(start))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) (interface{}, error) {
+	if err := s.authenticate(r); err != nil {
+		return nil, err
+	}
+
+	route, params := findRoute(r.Method, r.URL.Path)
+	if route == nil {
+		return nil, errNotFound("%s %s is not a known endpoint", r.Method, r.URL.Path)
+	}
+
+	if err := s.takeRateLimit(w, route.name); err != nil {
+		return nil, err
+	}
+
+	if isGzipped(r.Header) {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, errInput("body is not valid gzip: %v", err)
+		}
+		r.Body = zr
+	}
+
+	req := &request{Request: r, params: params, body: object{}}
+	if err := decodeBody(r, req.body); err != nil {
+		return nil, err
+	}
+
+	resp, err := route.handler(s, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		resp = object{}
+	}
+	return resp, nil
+}
+
+// authenticate checks the API key and the JWT of the request, like the API does for server side calls.
+func (s *Server) authenticate(r *http.Request) error {
+	if key := r.URL.Query().Get("api_key"); key != s.APIKey {
+		return errUnauthorized(codeAccessKey, "api_key not valid")
+	}
+	if t := r.Header.Get("Stream-Auth-Type"); t != "jwt" {
+		return errUnauthorized(codeAuthenticationFailed, "Stream-Auth-Type header must be jwt, got %q", t)
+	}
+
+	_, err := jwt.Parse(r.Header.Get("Authorization"), func(t *jwt.Token) (interface{}, error) {
+		if t.Method.Alg() != jwt.SigningMethodHS256.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %s", t.Method.Alg())
+		}
+		return []byte(s.APISecret), nil
+	})
+
+	var ve *jwt.ValidationError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &ve) && ve.Errors&jwt.ValidationErrorExpired != 0:
+		return errUnauthorized(codeTokenExpired, "token is expired")
+	case errors.As(err, &ve) && ve.Errors&jwt.ValidationErrorSignatureInvalid != 0:
+		return errUnauthorized(codeTokenSignatureInvalid, "token signature is invalid")
+	default:
+		return errUnauthorized(codeAuthenticationFailed, "token is not valid: %v", err)
+	}
+}
+
+func decodeBody(r *http.Request, body object) error {
+	if r.Body == nil {
+		return nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/json" {
+		return nil
+	}
+
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil && err != io.EOF {
+		return errInput("body is not valid JSON: %v", err)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func formatDuration(d time.Duration) string {
+	return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', 2, 64) + "ms"
+}
+
+// newID returns a random UUID.
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	h := hex.EncodeToString(b)
+	return strings.Join([]string{h[:8], h[8:12], h[12:16], h[16:20], h[20:]}, "-")
+}
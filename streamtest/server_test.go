@@ -0,0 +1,98 @@
+package streamtest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	stream "github.com/GetStream/stream-chat-go/v4"
+	"github.com/GetStream/stream-chat-go/v4/streamtest"
+)
+
+func newClient(t *testing.T, srv *streamtest.Server, secret string) *stream.Client {
+	t.Helper()
+
+	c, err := stream.NewClient(srv.APIKey, secret, stream.WithBaseURL(srv.URL))
+	require.NoError(t, err)
+	return c
+}
+
+func TestServer_Authentication(t *testing.T) {
+	srv := streamtest.NewServer("key", "secret")
+	defer srv.Close()
+
+	c := newClient(t, srv, "wrong secret")
+	_, err := c.UpsertUser(context.Background(), &stream.User{ID: "tommaso"})
+
+	var apiErr *stream.APIError
+	require.True(t, errors.As(err, &apiErr), "unexpected error: %v", err)
+	require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	require.Equal(t, stream.ErrorCodeTokenSignatureInvalid, apiErr.Code)
+}
+
+func TestServer_Messages(t *testing.T) {
+	ctx := context.Background()
+	srv := streamtest.NewServer("key", "secret")
+	defer srv.Close()
+
+	c := newClient(t, srv, srv.APISecret)
+	_, err := c.UpsertUsers(ctx, &stream.User{ID: "tommaso"}, &stream.User{ID: "thierry"})
+	require.NoError(t, err)
+
+	ch, err := c.CreateChannel(ctx, "messaging", "general", "tommaso", map[string]interface{}{
+		"members": []string{"tommaso", "thierry"},
+	})
+	require.NoError(t, err)
+	require.Len(t, ch.Members, 2)
+
+	msg, err := ch.SendMessage(ctx, &stream.Message{Text: "hello *world*"}, "thierry")
+	require.NoError(t, err)
+	require.NotEmpty(t, msg.ID)
+	require.Equal(t, "<p>hello *world*</p>\n", msg.HTML)
+
+	got, err := c.GetMessage(ctx, msg.ID)
+	require.NoError(t, err)
+	require.Equal(t, "hello *world*", got.Text)
+	require.Equal(t, "thierry", got.User.ID)
+}
+
+func TestServer_Reset(t *testing.T) {
+	ctx := context.Background()
+	srv := streamtest.NewServer("key", "secret")
+	defer srv.Close()
+
+	c := newClient(t, srv, srv.APISecret)
+	_, err := c.UpsertUser(ctx, &stream.User{ID: "tommaso"})
+	require.NoError(t, err)
+
+	srv.Reset()
+
+	users, err := c.QueryUsers(ctx, &stream.QueryOption{Filter: map[string]interface{}{"id": "tommaso"}})
+	require.NoError(t, err)
+	require.Empty(t, users)
+
+	_, err = c.GetChannelType(ctx, "messaging")
+	require.NoError(t, err, "the default channel types are restored")
+}
+
+func TestServer_RateLimits(t *testing.T) {
+	ctx := context.Background()
+	srv := streamtest.NewServer("key", "secret")
+	defer srv.Close()
+
+	c := newClient(t, srv, srv.APISecret)
+	_, err := c.GetMessage(ctx, "missing")
+	require.True(t, stream.IsNotFound(err), "unexpected error: %v", err)
+
+	var apiErr *stream.APIError
+	require.True(t, errors.As(err, &apiErr))
+	require.NotNil(t, apiErr.RateLimit)
+	require.Equal(t, apiErr.RateLimit.Limit-1, apiErr.RateLimit.Remaining)
+
+	limits, err := c.GetRateLimits(ctx, stream.WithServerSide(), stream.WithEndpoints("GetMessage"))
+	require.NoError(t, err)
+	require.Equal(t, apiErr.RateLimit.Remaining, limits.ServerSide["GetMessage"].Remaining)
+}
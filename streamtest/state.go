@@ -0,0 +1,658 @@
+package streamtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"time"
+)
+
+// state holds the data of the fake application.
+type state struct {
+	app          object
+	users        map[string]object
+	channels     map[string]*channel
+	messages     map[string]*message
+	channelTypes map[string]object
+	commands     map[string]object
+	devices      map[string][]object
+	mutes        []*mute
+	channelMutes []*channelMute
+	flags        []*flag
+	bans         []*ban
+	tasks        map[string]object
+	files        map[string]bool
+	rateLimits   map[string]*rateLimitWindow
+}
+
+func newState(now time.Time) *state {
+	st := &state{
+		app: object{
+			"name":                       "streamtest",
+			"organization":               "streamtest",
+			"disable_auth_checks":        false,
+			"disable_permissions_checks": false,
+			"multi_tenant_enabled":       false,
+			"async_url_enrich_enabled":   false,
+			"suspended":                  false,
+			"webhook_url":                "",
+			"push_notifications":         object{"apn": object{"enabled": false}, "firebase": object{"enabled": false}},
+		},
+		users:        make(map[string]object),
+		channels:     make(map[string]*channel),
+		messages:     make(map[string]*message),
+		channelTypes: make(map[string]object),
+		commands:     make(map[string]object),
+		devices:      make(map[string][]object),
+		tasks:        make(map[string]object),
+		files:        make(map[string]bool),
+		rateLimits:   make(map[string]*rateLimitWindow),
+	}
+
+	for _, cmd := range defaultCommands {
+		c := copyObject(cmd)
+		c["created_at"] = now
+		c["updated_at"] = now
+		st.commands[cmd["name"].(string)] = c
+	}
+	for _, name := range defaultChannelTypes {
+		st.channelTypes[name] = newChannelType(name, object{}, now)
+	}
+	return st
+}
+
+type channel struct {
+	typ string
+	id  string
+
+	// data holds the custom fields of the channel.
+	data      object
+	createdBy string
+	frozen    bool
+	disabled  bool
+
+	members  []*member
+	messages []*message
+	reads    map[string]time.Time
+	hidden   map[string]bool
+
+	createdAt     time.Time
+	updatedAt     time.Time
+	lastMessageAt *time.Time
+	truncatedAt   *time.Time
+}
+
+func (ch *channel) cid() string {
+	return ch.typ + ":" + ch.id
+}
+
+func (ch *channel) member(userID string) *member {
+	for _, m := range ch.members {
+		if m.userID == userID {
+			return m
+		}
+	}
+	return nil
+}
+
+func (ch *channel) memberIDs() []interface{} {
+	ids := make([]interface{}, 0, len(ch.members))
+	for _, m := range ch.members {
+		ids = append(ids, m.userID)
+	}
+	return ids
+}
+
+// addMessage inserts the message in the channel, keeping the messages sorted by creation time.
+func (ch *channel) addMessage(m *message) {
+	i := sort.Search(len(ch.messages), func(i int) bool {
+		return ch.messages[i].createdAt.After(m.createdAt)
+	})
+	ch.messages = append(ch.messages, nil)
+	copy(ch.messages[i+1:], ch.messages[i:])
+	ch.messages[i] = m
+
+	if m.parentID == "" && (ch.lastMessageAt == nil || m.createdAt.After(*ch.lastMessageAt)) {
+		t := m.createdAt
+		ch.lastMessageAt = &t
+	}
+}
+
+func (ch *channel) removeMessage(id string) {
+	for i, m := range ch.messages {
+		if m.id == id {
+			ch.messages = append(ch.messages[:i], ch.messages[i+1:]...)
+			return
+		}
+	}
+}
+
+type member struct {
+	userID           string
+	role             string
+	invited          bool
+	inviteAcceptedAt *time.Time
+	inviteRejectedAt *time.Time
+	createdAt        time.Time
+	updatedAt        time.Time
+}
+
+type message struct {
+	id       string
+	cid      string
+	userID   string
+	parentID string
+	typ      string
+	text     string
+
+	// fields holds the other fields sent by the client, like attachments and custom data.
+	fields     object
+	shadowed   bool
+	replyCount int
+	reactions  []*reaction
+
+	pinnedBy   string
+	pinnedAt   *time.Time
+	pinExpires *time.Time
+
+	createdAt time.Time
+	updatedAt time.Time
+	deletedAt *time.Time
+}
+
+// pinned reports whether the message is pinned and its pin is not expired.
+func (m *message) pinned() bool {
+	return m.pinnedAt != nil && (m.pinExpires == nil || time.Now().Before(*m.pinExpires))
+}
+
+type reaction struct {
+	messageID string
+	userID    string
+	typ       string
+	fields    object
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+type mute struct {
+	userID    string
+	targetID  string
+	expires   *time.Time
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+type channelMute struct {
+	userID    string
+	cid       string
+	expires   *time.Time
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+type flag struct {
+	userID          string
+	targetMessageID string
+	targetUserID    string
+	createdAt       time.Time
+}
+
+type ban struct {
+	targetID  string
+	bannedBy  string
+	cid       string
+	shadow    bool
+	reason    string
+	expires   *time.Time
+	createdAt time.Time
+}
+
+func (b *ban) active() bool {
+	return b.expires == nil || time.Now().Before(*b.expires)
+}
+
+// bannedFrom returns the active ban of the user in the channel, either global or specific to it.
+// Shadow bans are ignored.
+func (st *state) bannedFrom(userID, cid string) *ban {
+	for _, b := range st.bans {
+		if !b.shadow && b.targetID == userID && (b.cid == "" || b.cid == cid) && b.active() {
+			return b
+		}
+	}
+	return nil
+}
+
+// reservedUserFields are the user fields managed by the server.
+//nolint: gochecknoglobals
+var reservedUserFields = []string{
+	"created_at", "updated_at", "last_active", "deactivated_at", "deleted_at", "online",
+	"banned", "mutes", "channel_mutes", "unread_count", "total_unread_count", "unread_channels",
+}
+
+// ensureUser returns the user, creating it if needed, like the API does for the users
+// given in messages sent server side.
+func (s *Server) ensureUser(id string) object {
+	if u, ok := s.users[id]; ok {
+		return u
+	}
+	now := s.now()
+	u := object{"id": id, "role": "user", "created_at": now, "updated_at": now}
+	s.users[id] = u
+	return u
+}
+
+// userJSON returns the representation of the user embedded in other objects.
+func (s *Server) userJSON(id string) object {
+	u, ok := s.users[id]
+	if !ok {
+		return object{"id": id}
+	}
+
+	out := copyObject(u)
+	out["online"] = false
+	out["banned"] = s.bannedFrom(id, "") != nil
+	return out
+}
+
+// fullUserJSON returns the user with its mutes, as returned by user queries.
+func (s *Server) fullUserJSON(id string) object {
+	out := s.userJSON(id)
+
+	mutes := []interface{}{}
+	for _, m := range s.mutes {
+		if m.userID == id {
+			mutes = append(mutes, s.muteJSON(m))
+		}
+	}
+	out["mutes"] = mutes
+
+	channelMutes := []interface{}{}
+	for _, m := range s.channelMutes {
+		if m.userID == id {
+			channelMutes = append(channelMutes, s.channelMuteJSON(m))
+		}
+	}
+	out["channel_mutes"] = channelMutes
+	return out
+}
+
+func (s *Server) muteJSON(m *mute) object {
+	return object{
+		"user":       s.userJSON(m.userID),
+		"target":     s.userJSON(m.targetID),
+		"expires":    m.expires,
+		"created_at": m.createdAt,
+		"updated_at": m.updatedAt,
+	}
+}
+
+func (s *Server) channelMuteJSON(m *channelMute) object {
+	out := object{
+		"user":       s.userJSON(m.userID),
+		"expires":    m.expires,
+		"created_at": m.createdAt,
+		"updated_at": m.updatedAt,
+	}
+	if ch, ok := s.channels[m.cid]; ok {
+		out["channel"] = s.channelJSON(ch)
+	}
+	return out
+}
+
+func (s *Server) channelJSON(ch *channel) object {
+	out := copyObject(ch.data)
+	out["id"] = ch.id
+	out["type"] = ch.typ
+	out["cid"] = ch.cid()
+	out["created_by"] = s.userJSON(ch.createdBy)
+	out["frozen"] = ch.frozen
+	out["disabled"] = ch.disabled
+	out["member_count"] = len(ch.members)
+	out["created_at"] = ch.createdAt
+	out["updated_at"] = ch.updatedAt
+	if ch.lastMessageAt != nil {
+		out["last_message_at"] = *ch.lastMessageAt
+	}
+	if ch.truncatedAt != nil {
+		out["truncated_at"] = *ch.truncatedAt
+	}
+	if ct, ok := s.channelTypes[ch.typ]; ok {
+		config := copyObject(ct)
+		delete(config, "permissions")
+		out["config"] = config
+	}
+	return out
+}
+
+func (s *Server) memberJSON(ch *channel, m *member) object {
+	out := object{
+		"user_id":    m.userID,
+		"user":       s.userJSON(m.userID),
+		"role":       m.role,
+		"created_at": m.createdAt,
+		"updated_at": m.updatedAt,
+	}
+	if m.role == roleModerator {
+		out["is_moderator"] = true
+	}
+	if m.invited {
+		out["invited"] = true
+	}
+	if m.inviteAcceptedAt != nil {
+		out["invite_accepted_at"] = *m.inviteAcceptedAt
+	}
+	if m.inviteRejectedAt != nil {
+		out["invite_rejected_at"] = *m.inviteRejectedAt
+	}
+	if s.bannedFrom(m.userID, ch.cid()) != nil {
+		out["banned"] = true
+	}
+	if s.shadowBanned(m.userID, ch.cid()) {
+		out["shadow_banned"] = true
+	}
+	return out
+}
+
+func (s *Server) membersJSON(ch *channel, limit int) []interface{} {
+	members := make([]interface{}, 0, len(ch.members))
+	for _, m := range ch.members {
+		if limit > 0 && len(members) == limit {
+			break
+		}
+		members = append(members, s.memberJSON(ch, m))
+	}
+	return members
+}
+
+// channelState returns the channel with its members, latest messages and read state,
+// as returned by channel queries.
+func (s *Server) channelState(ch *channel, messageLimit, memberLimit int) object {
+	var visible []*message
+	for _, m := range ch.messages {
+		if m.parentID != "" && !boolField(m.fields, "show_in_channel") {
+			continue
+		}
+		visible = append(visible, m)
+	}
+	if messageLimit >= 0 && len(visible) > messageLimit {
+		visible = visible[len(visible)-messageLimit:]
+	}
+
+	messages := make([]interface{}, 0, len(visible))
+	for _, m := range visible {
+		messages = append(messages, s.messageJSON(m))
+	}
+
+	read := make([]interface{}, 0, len(ch.reads))
+	for _, m := range ch.members {
+		if t, ok := ch.reads[m.userID]; ok {
+			read = append(read, object{"user": s.userJSON(m.userID), "last_read": t})
+		}
+	}
+
+	return object{
+		"channel":       s.channelJSON(ch),
+		"members":       s.membersJSON(ch, memberLimit),
+		"messages":      messages,
+		"read":          read,
+		"watcher_count": 0,
+		"pinned_messages": func() []interface{} {
+			pinned := []interface{}{}
+			for _, m := range ch.messages {
+				if m.pinned() {
+					pinned = append(pinned, s.messageJSON(m))
+				}
+			}
+			return pinned
+		}(),
+	}
+}
+
+// reservedMessageFields are the message fields managed by the server.
+//nolint: gochecknoglobals
+var reservedMessageFields = []string{
+	"id", "text", "html", "type", "user", "user_id", "cid", "parent_id", "created_at", "updated_at",
+	"deleted_at", "latest_reactions", "own_reactions", "reaction_counts", "reaction_scores",
+	"reply_count", "shadowed", "pinned", "pinned_at", "pinned_by", "pin_expires", "duration",
+}
+
+func (s *Server) messageJSON(m *message) object {
+	out := copyObject(m.fields)
+
+	mentioned := []interface{}{}
+	if ids, ok := m.fields["mentioned_users"].([]interface{}); ok {
+		for _, id := range ids {
+			switch v := id.(type) {
+			case string:
+				mentioned = append(mentioned, s.userJSON(v))
+			case map[string]interface{}:
+				if id, ok := v["id"].(string); ok {
+					mentioned = append(mentioned, s.userJSON(id))
+				}
+			}
+		}
+	}
+	out["mentioned_users"] = mentioned
+	if _, ok := out["attachments"].([]interface{}); !ok {
+		out["attachments"] = []interface{}{}
+	}
+
+	counts := object{}
+	scores := object{}
+	latest := []interface{}{}
+	for i := len(m.reactions) - 1; i >= 0; i-- {
+		r := m.reactions[i]
+		counts[r.typ] = intField(counts, r.typ) + 1
+		scores[r.typ] = intField(scores, r.typ) + 1
+		if len(latest) < 10 {
+			latest = append(latest, s.reactionJSON(r))
+		}
+	}
+
+	out["id"] = m.id
+	out["cid"] = m.cid
+	out["text"] = m.text
+	out["html"] = renderHTML(m.text)
+	out["type"] = m.typ
+	out["user"] = s.userJSON(m.userID)
+	out["reaction_counts"] = counts
+	out["reaction_scores"] = scores
+	out["latest_reactions"] = latest
+	out["own_reactions"] = []interface{}{}
+	out["reply_count"] = m.replyCount
+	out["shadowed"] = m.shadowed
+	out["created_at"] = m.createdAt
+	out["updated_at"] = m.updatedAt
+	if m.parentID != "" {
+		out["parent_id"] = m.parentID
+	}
+	if m.deletedAt != nil {
+		out["deleted_at"] = *m.deletedAt
+	}
+
+	out["pinned"] = m.pinned()
+	if m.pinned() {
+		out["pinned_at"] = *m.pinnedAt
+		out["pinned_by"] = s.userJSON(m.pinnedBy)
+		out["pin_expires"] = m.pinExpires
+	}
+	return out
+}
+
+func (s *Server) reactionJSON(r *reaction) object {
+	out := copyObject(r.fields)
+	out["message_id"] = r.messageID
+	out["user_id"] = r.userID
+	out["user"] = s.userJSON(r.userID)
+	out["type"] = r.typ
+	out["score"] = 1
+	out["created_at"] = r.createdAt
+	out["updated_at"] = r.updatedAt
+	return out
+}
+
+func (s *Server) flagJSON(f *flag) object {
+	out := object{
+		"user":       s.userJSON(f.userID),
+		"created_at": f.createdAt,
+		"updated_at": f.createdAt,
+	}
+	if m, ok := s.messages[f.targetMessageID]; ok {
+		out["message"] = s.messageJSON(m)
+	}
+	if f.targetUserID != "" {
+		out["target_user"] = s.userJSON(f.targetUserID)
+	}
+	return out
+}
+
+// renderHTML returns the HTML rendering of a message text, like the markdown renderer of the API
+// does for plain text.
+func renderHTML(text string) string {
+	if text == "" {
+		return ""
+	}
+	return "<p>" + html.EscapeString(text) + "</p>\n"
+}
+
+// newTask stores a completed async task with the given result and returns its ID.
+func (s *Server) newTask(result object) string {
+	now := s.now()
+	id := newID()
+	s.tasks[id] = object{
+		"task_id":    id,
+		"status":     "completed",
+		"result":     result,
+		"created_at": now,
+		"updated_at": now,
+	}
+	return id
+}
+
+// toDocument converts a value to its generic JSON representation, used to evaluate filters.
+func toDocument(v interface{}) object {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("streamtest: cannot marshal %T: %v", v, err))
+	}
+	doc := object{}
+	_ = json.Unmarshal(b, &doc)
+	return doc
+}
+
+func copyObject(o object) object {
+	out := make(object, len(o))
+	for k, v := range o {
+		out[k] = v
+	}
+	return out
+}
+
+// removeFields returns a copy of o without the given keys.
+func removeFields(o object, keys []string) object {
+	out := copyObject(o)
+	for _, k := range keys {
+		delete(out, k)
+	}
+	return out
+}
+
+func stringField(o object, key string) string {
+	s, _ := o[key].(string)
+	return s
+}
+
+func boolField(o object, key string) bool {
+	b, _ := o[key].(bool)
+	return b
+}
+
+func intField(o object, key string) int {
+	switch v := o[key].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
+
+func objectField(o object, key string) object {
+	m, _ := o[key].(map[string]interface{})
+	return m
+}
+
+// stringList returns the IDs listed in a field holding either strings or objects with an id,
+// like the members of a channel.
+func stringList(o object, key string, idKeys ...string) []string {
+	items, _ := o[key].([]interface{})
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		if id := idOf(item, idKeys...); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// idOf returns the ID given either as a string or as an object with an id field.
+func idOf(v interface{}, idKeys ...string) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		for _, k := range append(idKeys, "id") {
+			if id, ok := v[k].(string); ok && id != "" {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
+// timeField parses a time given as a RFC 3339 string.
+func timeField(o object, key string) (*time.Time, error) {
+	v, ok := o[key].(string)
+	if !ok || v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		return nil, errInput("%s is not a valid time: %v", key, err)
+	}
+	t = t.UTC()
+	return &t, nil
+}
+
+// setPath sets the value at a dotted path, creating the intermediate objects.
+func setPath(o object, path string, value interface{}) {
+	keys := strings.Split(path, ".")
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := o[k].(map[string]interface{})
+		if !ok {
+			next = object{}
+		} else {
+			next = copyObject(next)
+		}
+		o[k] = next
+		o = next
+	}
+	o[keys[len(keys)-1]] = value
+}
+
+// unsetPath removes the value at a dotted path.
+func unsetPath(o object, path string) {
+	keys := strings.Split(path, ".")
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := o[k].(map[string]interface{})
+		if !ok {
+			return
+		}
+		next = copyObject(next)
+		o[k] = next
+		o = next
+	}
+	delete(o, keys[len(keys)-1])
+}
@@ -0,0 +1,263 @@
+package streamtest
+
+import (
+	"sort"
+	"time"
+)
+
+func (s *Server) upsertUsers(r *request) (interface{}, error) {
+	users := objectField(r.body, "users")
+	if len(users) == 0 {
+		return nil, errInput("users is a required field")
+	}
+
+	now := s.now()
+	resp := object{}
+	for id, v := range users {
+		fields, ok := v.(map[string]interface{})
+		if !ok || id == "" {
+			return nil, errInput("users must be a map of user IDs to users")
+		}
+
+		u := removeFields(fields, reservedUserFields)
+		u["id"] = id
+		if stringField(u, "role") == "" {
+			u["role"] = "user"
+		}
+		u["created_at"] = now
+		if old, ok := s.users[id]; ok {
+			u["created_at"] = old["created_at"]
+			for _, k := range []string{"deactivated_at", "deleted_at"} {
+				if v, ok := old[k]; ok {
+					u[k] = v
+				}
+			}
+		}
+		u["updated_at"] = now
+		s.users[id] = u
+		resp[id] = s.userJSON(id)
+	}
+	return object{"users": resp}, nil
+}
+
+func (s *Server) updateUsersPartial(r *request) (interface{}, error) {
+	updates, _ := r.body["users"].([]interface{})
+	if len(updates) == 0 {
+		return nil, errInput("users is a required field")
+	}
+
+	now := s.now()
+	resp := object{}
+	for _, v := range updates {
+		update, _ := v.(map[string]interface{})
+		id := stringField(update, "id")
+		old, ok := s.users[id]
+		if !ok {
+			return nil, errInput("user %q does not exist", id)
+		}
+
+		u := copyObject(old)
+		for k, v := range objectField(update, "set") {
+			if !isReserved(k, reservedUserFields) {
+				setPath(u, k, v)
+			}
+		}
+		unset, _ := update["unset"].([]interface{})
+		for _, k := range unset {
+			if k, ok := k.(string); ok && !isReserved(k, reservedUserFields) && k != "id" {
+				unsetPath(u, k)
+			}
+		}
+		u["updated_at"] = now
+		s.users[id] = u
+		resp[id] = s.userJSON(id)
+	}
+	return object{"users": resp}, nil
+}
+
+func (s *Server) queryUsers(r *request) (interface{}, error) {
+	q, err := r.payload()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(s.users))
+	for id := range s.users {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	docs := make([]object, len(ids))
+	for i, id := range ids {
+		docs[i] = toDocument(s.fullUserJSON(id))
+	}
+
+	indexes, err := query(docs, objectField(q, "filter_conditions"), q["sort"],
+		object{"field": "created_at", "direction": -1})
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := paginate(len(indexes), q, 100)
+	users := make([]interface{}, 0, end-start)
+	for _, i := range indexes[start:end] {
+		users = append(users, docs[i])
+	}
+	return object{"users": users}, nil
+}
+
+func (s *Server) deleteUser(r *request) (interface{}, error) {
+	id := r.param(0)
+	if _, ok := s.users[id]; !ok {
+		return nil, errNotFound("user %q does not exist", id)
+	}
+
+	params := r.URL.Query()
+	resp := object{"user": s.userJSON(id)}
+	s.removeUser(id, params.Get("hard_delete") == "true", params.Get("mark_messages_deleted") == "true")
+	return resp, nil
+}
+
+func (s *Server) deleteUsers(r *request) (interface{}, error) {
+	ids := stringList(r.body, "user_ids")
+	if len(ids) == 0 {
+		return nil, errInput("user_ids is a required field")
+	}
+
+	result := object{}
+	for _, id := range ids {
+		if _, ok := s.users[id]; !ok {
+			result[id] = object{"status": "error", "error": "user does not exist"}
+			continue
+		}
+		s.removeUser(id, stringField(r.body, "user") == "hard", stringField(r.body, "messages") != "")
+		result[id] = object{"status": "ok"}
+	}
+	return object{"task_id": s.newTask(result)}, nil
+}
+
+// removeUser deletes the user, either by marking it as deleted or by removing it
+// with its memberships, and optionally deletes its messages.
+func (s *Server) removeUser(id string, hard, deleteMessages bool) {
+	now := s.now()
+	if deleteMessages {
+		for _, m := range s.messages {
+			if m.userID == id && m.deletedAt == nil {
+				m.deletedAt = &now
+				m.typ = "deleted"
+			}
+		}
+	}
+
+	if !hard {
+		u := copyObject(s.users[id])
+		u["deleted_at"] = now
+		s.users[id] = u
+		return
+	}
+
+	delete(s.users, id)
+	delete(s.devices, id)
+	for _, ch := range s.channels {
+		for i, m := range ch.members {
+			if m.userID == id {
+				ch.members = append(ch.members[:i], ch.members[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+func (s *Server) exportUser(r *request) (interface{}, error) {
+	id := r.param(0)
+	if _, ok := s.users[id]; !ok {
+		return nil, errNotFound("user %q does not exist", id)
+	}
+
+	messages := []interface{}{}
+	reactions := []interface{}{}
+	for _, ch := range s.channels {
+		for _, m := range ch.messages {
+			if m.userID == id {
+				messages = append(messages, s.messageJSON(m))
+			}
+			for _, re := range m.reactions {
+				if re.userID == id {
+					reactions = append(reactions, s.reactionJSON(re))
+				}
+			}
+		}
+	}
+	return object{"user": s.userJSON(id), "messages": messages, "reactions": reactions}, nil
+}
+
+func (s *Server) deactivateUser(r *request) (interface{}, error) {
+	return s.setUserField(r.param(0), "deactivated_at", s.now())
+}
+
+func (s *Server) reactivateUser(r *request) (interface{}, error) {
+	return s.setUserField(r.param(0), "deactivated_at", nil)
+}
+
+func (s *Server) setUserField(id, key string, value interface{}) (interface{}, error) {
+	old, ok := s.users[id]
+	if !ok {
+		return nil, errNotFound("user %q does not exist", id)
+	}
+
+	u := copyObject(old)
+	if value == nil {
+		delete(u, key)
+	} else {
+		u[key] = value
+	}
+	s.users[id] = u
+	return object{"user": s.userJSON(id)}, nil
+}
+
+func (s *Server) sendUserCustomEvent(r *request) (interface{}, error) {
+	if _, ok := s.users[r.param(0)]; !ok {
+		return nil, errNotFound("user %q does not exist", r.param(0))
+	}
+
+	event := objectField(r.body, "event")
+	if stringField(event, "type") == "" {
+		return nil, errInput("event.type is a required field")
+	}
+	event = copyObject(event)
+	event["created_at"] = s.now()
+	return object{"event": event}, nil
+}
+
+// existingUsers checks that the users exist, like the API does for the members of a channel.
+func (s *Server) existingUsers(ids []string, field string) error {
+	var missing []string
+	for _, id := range ids {
+		if _, ok := s.users[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		return errInput("The following users are specified in %s but don't exist: %v", field, missing)
+	}
+	return nil
+}
+
+// minutes returns the time the given number of minutes after now, used by the timeout of mutes and bans.
+func minutes(now time.Time, o object, key string) *time.Time {
+	n := intField(o, key)
+	if n <= 0 {
+		return nil
+	}
+	t := now.Add(time.Duration(n) * time.Minute)
+	return &t
+}
+
+func isReserved(key string, reserved []string) bool {
+	for _, k := range reserved {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
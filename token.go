@@ -0,0 +1,106 @@
+package stream_chat // nolint: golint
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// ErrInvalidToken is returned when a user token cannot be verified, either because it is
+// malformed, it is not signed with one of the client secrets, or it is expired.
+var ErrInvalidToken = errors.New("chat-client: invalid token")
+
+// CreateToken creates a new token for user with optional expire time.
+// Zero time is assumed to be no expire.
+func (c *Client) CreateToken(userID string, expire time.Time, issuedAt ...time.Time) (string, error) {
+	claims := make(map[string]interface{})
+	if !expire.IsZero() {
+		claims["exp"] = expire
+	}
+	if len(issuedAt) > 0 {
+		claims["iat"] = issuedAt[0]
+	}
+
+	return c.CreateTokenWithClaims(userID, claims)
+}
+
+// CreateTokenWithClaims creates a new token for user carrying the given extra claims.
+// The user_id claim is always set to userID. time.Time values are encoded as Unix timestamps,
+// so registered claims like exp, iat and nbf can be given as times.
+func (c *Client) CreateTokenWithClaims(userID string, claims map[string]interface{}) (string, error) {
+	if userID == "" {
+		return "", errors.New("user ID is empty")
+	}
+
+	mapClaims := make(jwt.MapClaims, len(claims)+1)
+	for k, v := range claims {
+		if t, ok := v.(time.Time); ok {
+			v = t.Unix()
+		}
+		mapClaims[k] = v
+	}
+	mapClaims["user_id"] = userID
+
+	return c.createToken(mapClaims)
+}
+
+func (c *Client) createToken(claims jwt.Claims) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(c.apiSecret)
+}
+
+// ParseUserToken verifies the signature and the exp, iat and nbf claims of a user token
+// and returns its claims. Tokens signed with one of the secrets given with
+// WithPreviousSecrets are accepted as well.
+func (c *Client) ParseUserToken(token string) (map[string]interface{}, error) {
+	var lastErr error
+	for _, secret := range c.verificationSecrets() {
+		claims, err := parseToken(token, secret)
+		if err == nil {
+			return claims, nil
+		}
+		lastErr = err
+
+		var ve *jwt.ValidationError
+		if !errors.As(err, &ve) || ve.Errors&jwt.ValidationErrorSignatureInvalid == 0 {
+			// the token is signed with this secret but is not valid, the others won't help
+			break
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %v", ErrInvalidToken, lastErr)
+}
+
+// VerifyUserToken verifies a user token like ParseUserToken and returns the ID of its user.
+func (c *Client) VerifyUserToken(token string) (string, error) {
+	claims, err := c.ParseUserToken(token)
+	if err != nil {
+		return "", err
+	}
+
+	userID, _ := claims["user_id"].(string)
+	if userID == "" {
+		return "", fmt.Errorf("%w: user_id claim is missing", ErrInvalidToken)
+	}
+	return userID, nil
+}
+
+// verificationSecrets returns the current secret followed by the previous ones.
+func (c *Client) verificationSecrets() [][]byte {
+	return append([][]byte{c.apiSecret}, c.previousSecrets...)
+}
+
+func parseToken(token string, secret []byte) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		if t.Method.Alg() != jwt.SigningMethodHS256.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %s", t.Method.Alg())
+		}
+		return secret, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
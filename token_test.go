@@ -0,0 +1,102 @@
+package stream_chat // nolint: golint
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_CreateTokenWithClaims(t *testing.T) {
+	c, err := NewClient("key", "secret")
+	require.NoError(t, err)
+
+	expire := time.Now().Add(time.Hour)
+	token, err := c.CreateTokenWithClaims("tommaso", map[string]interface{}{
+		"exp":     expire,
+		"role":    "admin",
+		"user_id": "ignored",
+	})
+	require.NoError(t, err)
+
+	claims, err := c.ParseUserToken(token)
+	require.NoError(t, err)
+	require.Equal(t, "tommaso", claims["user_id"])
+	require.Equal(t, "admin", claims["role"])
+	require.EqualValues(t, expire.Unix(), claims["exp"])
+
+	_, err = c.CreateTokenWithClaims("", nil)
+	require.Error(t, err)
+}
+
+func TestClient_VerifyUserToken(t *testing.T) {
+	c, err := NewClient("key", "secret")
+	require.NoError(t, err)
+
+	t.Run("valid", func(t *testing.T) {
+		token, err := c.CreateToken("tommaso", time.Now().Add(time.Hour))
+		require.NoError(t, err)
+
+		userID, err := c.VerifyUserToken(token)
+		require.NoError(t, err)
+		require.Equal(t, "tommaso", userID)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		token, err := c.CreateToken("tommaso", time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+
+		_, err = c.VerifyUserToken(token)
+		require.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("signed with another secret", func(t *testing.T) {
+		other, err := NewClient("key", "other")
+		require.NoError(t, err)
+		token, err := other.CreateToken("tommaso", time.Time{})
+		require.NoError(t, err)
+
+		_, err = c.VerifyUserToken(token)
+		require.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("server token", func(t *testing.T) {
+		_, err := c.VerifyUserToken(c.authToken)
+		require.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("unexpected signing method", func(t *testing.T) {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS512, jwt.MapClaims{"user_id": "tommaso"}).
+			SignedString([]byte("secret"))
+		require.NoError(t, err)
+
+		_, err = c.VerifyUserToken(token)
+		require.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
+
+func TestClient_VerifyUserToken_SecretRotation(t *testing.T) {
+	old, err := NewClient("key", "old-secret")
+	require.NoError(t, err)
+	oldToken, err := old.CreateToken("tommaso", time.Time{})
+	require.NoError(t, err)
+	expiredToken, err := old.CreateToken("tommaso", time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+
+	c, err := NewClient("key", "new-secret", WithPreviousSecrets("older-secret", "old-secret"))
+	require.NoError(t, err)
+
+	userID, err := c.VerifyUserToken(oldToken)
+	require.NoError(t, err)
+	require.Equal(t, "tommaso", userID)
+
+	_, err = c.VerifyUserToken(expiredToken)
+	require.ErrorIs(t, err, ErrInvalidToken)
+	require.Contains(t, err.Error(), "expired")
+
+	newToken, err := c.CreateToken("tommaso", time.Time{})
+	require.NoError(t, err)
+	_, err = old.VerifyUserToken(newToken)
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
@@ -0,0 +1,144 @@
+package stream_chat // nolint: golint
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Tracer creates a span for every API call, set with WithTracer.
+// The streamotel module provides an OpenTelemetry implementation.
+type Tracer interface {
+	// Start starts the span of an API call and returns a context carrying it, which is used
+	// for the request.
+	Start(ctx context.Context, operation string, attrs SpanAttributes) (context.Context, Span)
+	// Inject adds the trace context of ctx to the headers of the outgoing request.
+	Inject(ctx context.Context, header http.Header)
+}
+
+// Span is the span of an API call.
+type Span interface {
+	// End ends the span with the outcome of the call. statusCode is the status of the last
+	// response, zero if no response was received. err is the error returned by the call, an
+	// *APIError for non successful responses.
+	End(statusCode int, err error)
+}
+
+// SpanAttributes describes an API call. The IDs are set when they are part of the request.
+type SpanAttributes struct {
+	Method      string
+	Path        string
+	ChannelType string
+	ChannelID   string
+	UserID      string
+	MessageID   string
+}
+
+// WithTracer sets the tracer creating a span for every API call.
+func WithTracer(tracer Tracer) ClientOption {
+	return func(o *clientOptions) {
+		o.tracer = tracer
+	}
+}
+
+// startSpan starts the span of an API call, named after its endpoint. The returned function
+// ends it with the last response and the error of the call.
+func (c *Client) startSpan(ctx context.Context, method, path string, params url.Values, data interface{}) (context.Context, func(*http.Response, error)) {
+	if c.tracer == nil {
+		return ctx, func(*http.Response, error) {}
+	}
+
+	operation := endpointName(method, path)
+	if operation == "" {
+		operation = "HTTP " + method
+	}
+
+	ctx, span := c.tracer.Start(ctx, operation, spanAttributes(method, path, params, data))
+	return ctx, func(resp *http.Response, err error) {
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+		} else if apiErr, ok := asAPIError(err); ok {
+			statusCode = apiErr.StatusCode
+		}
+		span.End(statusCode, err)
+	}
+}
+
+// spanAttributes extracts the IDs of the channel, user and message of an API call from its
+// path and query parameters, and from the top level fields of its body: it is not encoded to
+// keep large payloads like imports cheap to trace.
+func spanAttributes(method, path string, params url.Values, data interface{}) SpanAttributes {
+	attrs := SpanAttributes{Method: method, Path: "/" + strings.Trim(path, "/")}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := range segments {
+		if s, err := url.PathUnescape(segments[i]); err == nil {
+			segments[i] = s
+		}
+	}
+	switch {
+	case segments[0] == "channels" && len(segments) >= 3:
+		attrs.ChannelType = segments[1]
+		if segments[2] != "query" {
+			attrs.ChannelID = segments[2]
+		}
+	case segments[0] == "messages" && len(segments) >= 2:
+		attrs.MessageID = segments[1]
+	case segments[0] == "users" && len(segments) >= 2 && segments[1] != "delete":
+		attrs.UserID = segments[1]
+	}
+
+	if attrs.UserID == "" {
+		attrs.UserID = params.Get("user_id")
+	}
+	if attrs.ChannelType == "" && params.Get("type") != "" {
+		attrs.ChannelType, attrs.ChannelID = params.Get("type"), params.Get("id")
+	}
+
+	var message messageRequestMessage
+	switch body := data.(type) {
+	case messageRequest:
+		message = body.Message
+	case *messageRequest:
+		message = body.Message
+	case map[string]interface{}:
+		for _, id := range []string{stringValue(body["user_id"]), idValue(body["user"])} {
+			if attrs.UserID == "" {
+				attrs.UserID = id
+			}
+		}
+		if typ := stringValue(body["type"]); attrs.ChannelType == "" && typ != "" {
+			attrs.ChannelType, attrs.ChannelID = typ, stringValue(body["id"])
+		}
+	}
+	if attrs.UserID == "" {
+		attrs.UserID = message.User.ID
+	}
+	if attrs.MessageID == "" {
+		attrs.MessageID = message.ID
+	}
+	return attrs
+}
+
+func stringValue(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+// idValue returns the ID of a user given as a *User or as a map with an id field, or an
+// empty string.
+func idValue(v interface{}) string {
+	switch o := v.(type) {
+	case map[string]interface{}:
+		return stringValue(o["id"])
+	case map[string]string:
+		return o["id"]
+	case *User:
+		if o != nil {
+			return o.ID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,87 @@
+package stream_chat // nolint: golint
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpanAttributes(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		params url.Values
+		data   interface{}
+		want   SpanAttributes
+	}{
+		{
+			name:   "send message",
+			method: http.MethodPost,
+			path:   "channels/messaging/general/message",
+			data:   (&Message{ID: "msg", User: &User{ID: "tommaso"}}).toRequest(),
+			want: SpanAttributes{
+				Method: http.MethodPost, Path: "/channels/messaging/general/message",
+				ChannelType: "messaging", ChannelID: "general", UserID: "tommaso", MessageID: "msg",
+			},
+		},
+		{
+			name:   "escaped path",
+			method: http.MethodDelete,
+			path:   "messages/" + url.PathEscape("a/b"),
+			params: url.Values{"user_id": {"tommaso"}},
+			want:   SpanAttributes{Method: http.MethodDelete, Path: "/messages/a%2Fb", MessageID: "a/b", UserID: "tommaso"},
+		},
+		{
+			name:   "channel without ID",
+			method: http.MethodPost,
+			path:   "channels/messaging/query",
+			data:   map[string]interface{}{"created_by": map[string]interface{}{"id": "tommaso"}},
+			want:   SpanAttributes{Method: http.MethodPost, Path: "/channels/messaging/query", ChannelType: "messaging"},
+		},
+		{
+			name:   "channel in the body",
+			method: http.MethodPost,
+			path:   "moderation/ban",
+			data:   map[string]interface{}{"type": "messaging", "id": "general", "user_id": "admin"},
+			want: SpanAttributes{
+				Method: http.MethodPost, Path: "/moderation/ban",
+				ChannelType: "messaging", ChannelID: "general", UserID: "admin",
+			},
+		},
+		{
+			name:   "user in the body",
+			method: http.MethodPost,
+			path:   "channels/messaging/general/file",
+			data:   map[string]interface{}{"user": &User{ID: "tommaso"}},
+			want: SpanAttributes{
+				Method: http.MethodPost, Path: "/channels/messaging/general/file",
+				ChannelType: "messaging", ChannelID: "general", UserID: "tommaso",
+			},
+		},
+		{
+			name:   "other bodies are not read",
+			method: http.MethodPost,
+			path:   "channels/messaging/general/import",
+			data:   map[string]interface{}{"messages": []*Message{{ID: "msg", User: &User{ID: "tommaso"}}}},
+			want: SpanAttributes{
+				Method: http.MethodPost, Path: "/channels/messaging/general/import",
+				ChannelType: "messaging", ChannelID: "general",
+			},
+		},
+		{
+			name:   "user path",
+			method: http.MethodPost,
+			path:   "users/tommaso/deactivate",
+			want:   SpanAttributes{Method: http.MethodPost, Path: "/users/tommaso/deactivate", UserID: "tommaso"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, spanAttributes(tt.method, tt.path, tt.params, tt.data))
+		})
+	}
+}
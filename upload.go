@@ -0,0 +1,286 @@
+package stream_chat // nolint: golint
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"path/filepath"
+	"strings"
+)
+
+// defaultMaxUploadSize is the largest file accepted by the Stream CDN.
+const defaultMaxUploadSize = 100 << 20
+
+// ErrFileTooLarge is returned when a file sent with SendFile or SendImage exceeds
+// the maximum upload size of the client.
+var ErrFileTooLarge = errors.New("chat-client: file too large")
+
+type sendFileResponse struct {
+	File string `json:"file"`
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
+
+// this makes possible to set content type.
+type multipartForm struct {
+	*multipart.Writer
+}
+
+// CreateFormFile is a convenience wrapper around CreatePart. It creates
+// a new form-data header with the provided field name, file name and content type.
+func (form *multipartForm) CreateFormFile(fieldName, filename, contentType string) (io.Writer, error) {
+	h := make(textproto.MIMEHeader)
+
+	h.Set("Content-Disposition",
+		fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			escapeQuotes(fieldName), escapeQuotes(filename)))
+
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	h.Set("Content-Type", contentType)
+
+	return form.Writer.CreatePart(h)
+}
+
+func (form *multipartForm) setData(fieldName string, data interface{}) error {
+	field, err := form.CreateFormField(fieldName)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(field).Encode(data)
+}
+
+func (form *multipartForm) setFile(fieldName string, r io.Reader, fileName, contentType string) error {
+	file, err := form.CreateFormFile(fieldName, fileName, contentType)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(file, r)
+
+	return err
+}
+
+// write writes the whole upload form for the file read from r.
+func (form *multipartForm) write(opts SendFileRequest, r io.Reader) error {
+	if err := form.setData("user", opts.User); err != nil {
+		return err
+	}
+	if err := form.setFile("file", r, opts.FileName, opts.ContentType); err != nil {
+		return err
+	}
+	return form.Close()
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+// formOverhead returns the size of the upload form without the file content.
+func formOverhead(opts SendFileRequest, boundary string) (int64, error) {
+	var cw countingWriter
+
+	form := multipartForm{multipart.NewWriter(&cw)}
+	if err := form.SetBoundary(boundary); err != nil {
+		return 0, err
+	}
+	if err := form.write(opts, strings.NewReader("")); err != nil {
+		return 0, err
+	}
+	return cw.n, nil
+}
+
+// readerSize returns the number of bytes left in r, or -1 if it cannot be known
+// without reading it.
+func readerSize(r io.Reader) int64 {
+	switch t := r.(type) {
+	case interface{ Len() int }:
+		return int64(t.Len())
+	case io.Seeker:
+		cur, err := t.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		end, err := t.Seek(0, io.SeekEnd)
+		if err != nil {
+			return -1
+		}
+		if _, err := t.Seek(cur, io.SeekStart); err != nil {
+			return -1
+		}
+		return end - cur
+	default:
+		return -1
+	}
+}
+
+// sniffLen is the number of bytes used by http.DetectContentType.
+const sniffLen = 512
+
+// detectContentType guesses the content type of the file from its first bytes and its name.
+// It returns the content type and a reader yielding the whole content of r. For images, the
+// name is only used when the content is not recognized, so that it cannot pass text as an image.
+func detectContentType(r io.Reader, fileName string, image bool) (string, io.Reader, error) {
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", nil, err
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+
+	// the extension is more specific than generic sniffed types, for example for JSON files
+	if contentType == "application/octet-stream" || (!image && strings.HasPrefix(contentType, "text/plain")) {
+		if byExt := mime.TypeByExtension(filepath.Ext(fileName)); byExt != "" {
+			contentType = byExt
+		}
+	}
+
+	return contentType, io.MultiReader(bytes.NewReader(head), r), nil
+}
+
+// progressReader reports the number of bytes read from r.
+type progressReader struct {
+	r        io.Reader
+	total    int64
+	sent     int64
+	progress func(sent, total int64)
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.sent += int64(n)
+		r.progress(r.sent, r.total)
+	}
+	return n, err
+}
+
+// maxSizeReader fails with ErrFileTooLarge once more than max bytes are read.
+type maxSizeReader struct {
+	r   io.Reader
+	max int64
+	n   int64
+}
+
+func (r *maxSizeReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	if r.n > r.max {
+		return n, fmt.Errorf("%w: more than %d bytes", ErrFileTooLarge, r.max)
+	}
+	return n, err
+}
+
+// sendFile uploads the file as a multipart form. The form is streamed to the server as it is
+// read, and its length is sent upfront when the size of the reader is known.
+// If image is true, files of other content types are rejected.
+func (c *Client) sendFile(ctx context.Context, link string, opts SendFileRequest, image bool) (string, error) {
+	if opts.User == nil {
+		return "", errors.New("user is nil")
+	}
+	if opts.Reader == nil {
+		return "", errors.New("reader is nil")
+	}
+
+	size := readerSize(opts.Reader)
+	if c.maxUploadSize > 0 && size > c.maxUploadSize {
+		return "", fmt.Errorf("%w: %d bytes, the limit is %d", ErrFileTooLarge, size, c.maxUploadSize)
+	}
+
+	file := opts.Reader
+	if opts.ContentType == "" {
+		contentType, r, err := detectContentType(file, opts.FileName, image)
+		if err != nil {
+			return "", err
+		}
+		opts.ContentType, file = contentType, r
+	}
+
+	if image && !strings.HasPrefix(opts.ContentType, "image/") {
+		return "", fmt.Errorf("content type %q is not an image", opts.ContentType)
+	}
+
+	if c.maxUploadSize > 0 {
+		file = &maxSizeReader{r: file, max: c.maxUploadSize}
+	}
+	if opts.Progress != nil {
+		file = &progressReader{r: file, total: size, progress: opts.Progress}
+	}
+
+	pr, pw := io.Pipe()
+	// the transport closes the body once done, this also covers requests that never reach it
+	defer pr.Close()
+
+	var zw *gzip.Writer
+	out := io.Writer(pw)
+	if c.compressUpload(opts.ContentType, size, image) {
+		zw = gzip.NewWriter(pw)
+		out = zw
+	}
+	form := multipartForm{multipart.NewWriter(out)}
+
+	ctx, endSpan := c.startSpan(ctx, http.MethodPost, link, nil, map[string]interface{}{"user": opts.User})
+
+	r, err := c.newRequest(ctx, http.MethodPost, link, nil, pr)
+	if err != nil {
+		endSpan(nil, err)
+		return "", err
+	}
+
+	r.Header.Set("Content-Type", form.FormDataContentType())
+
+	if zw != nil {
+		// the compressed length is not known upfront
+		r.Header.Set("Content-Encoding", gzipEncoding)
+	} else if size >= 0 {
+		overhead, err := formOverhead(opts, form.Boundary())
+		if err != nil {
+			endSpan(nil, err)
+			return "", err
+		}
+		r.ContentLength = overhead + size
+	}
+
+	go func() {
+		err := form.write(opts, file)
+		if err == nil && zw != nil {
+			err = zw.Close()
+		}
+		_ = pw.CloseWithError(err)
+	}()
+
+	res, err := c.do(r)
+	if err != nil {
+		endSpan(nil, err)
+		return "", err
+	}
+
+	var resp sendFileResponse
+	err = c.parseResponse(res, &resp)
+	endSpan(res, err)
+	if err != nil {
+		return "", err
+	}
+
+	return resp.File, err
+}
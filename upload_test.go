@@ -0,0 +1,184 @@
+package stream_chat // nolint: golint
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_SendFileStreaming(t *testing.T) {
+	ctx := context.Background()
+
+	var (
+		calls         int32
+		contentLength int64
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		contentLength = r.ContentLength
+
+		file, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		defer file.Close()
+
+		content, err := ioutil.ReadAll(file)
+		require.NoError(t, err)
+		require.Equal(t, "hello world", string(content))
+		require.Equal(t, "hello.txt", header.Filename)
+		require.Equal(t, "text/plain", header.Header.Get("Content-Type"))
+		require.JSONEq(t, `{"id":"user"}`, r.FormValue("user"))
+
+		_, _ = w.Write([]byte(`{"file":"https://cdn/hello.txt"}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("key", "secret", WithBaseURL(srv.URL), WithMaxUploadSize(20))
+	require.NoError(t, err)
+	ch := c.Channel("messaging", "general")
+
+	request := func(r io.Reader) SendFileRequest {
+		return SendFileRequest{Reader: r, FileName: "hello.txt", User: &User{ID: "user"}, ContentType: "text/plain"}
+	}
+
+	t.Run("known size sends content length", func(t *testing.T) {
+		loc, err := ch.SendFile(ctx, request(strings.NewReader("hello world")))
+		require.NoError(t, err)
+		require.Equal(t, "https://cdn/hello.txt", loc)
+		require.Greater(t, contentLength, int64(len("hello world")))
+	})
+
+	t.Run("unknown size is streamed", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		go func() {
+			_, _ = pw.Write([]byte("hello "))
+			_, _ = pw.Write([]byte("world"))
+			_ = pw.Close()
+		}()
+
+		_, err := ch.SendFile(ctx, request(pr))
+		require.NoError(t, err)
+		require.EqualValues(t, -1, contentLength)
+	})
+
+	t.Run("oversized file with known size is rejected upfront", func(t *testing.T) {
+		before := atomic.LoadInt32(&calls)
+
+		_, err := ch.SendFile(ctx, request(strings.NewReader(strings.Repeat("a", 21))))
+		require.True(t, errors.Is(err, ErrFileTooLarge), err)
+		require.Equal(t, before, atomic.LoadInt32(&calls))
+	})
+
+	t.Run("oversized stream is aborted", func(t *testing.T) {
+		_, err := ch.SendFile(ctx, request(ioutil.NopCloser(strings.NewReader(strings.Repeat("a", 100)))))
+		require.True(t, errors.Is(err, ErrFileTooLarge), err)
+	})
+}
+
+func TestReaderSize(t *testing.T) {
+	r := strings.NewReader("hello world")
+	require.EqualValues(t, 11, readerSize(r))
+
+	_, err := r.Seek(6, io.SeekStart)
+	require.NoError(t, err)
+	require.EqualValues(t, 5, readerSize(r))
+
+	require.EqualValues(t, -1, readerSize(ioutil.NopCloser(r)))
+}
+
+func TestDetectContentType(t *testing.T) {
+	png := "\x89PNG\x0D\x0A\x1A\x0A" + strings.Repeat("\x00", 20)
+
+	tests := []struct {
+		name     string
+		content  string
+		fileName string
+		want     string
+	}{
+		{"sniffed", png, "image", "image/png"},
+		{"sniffed wins over extension", png, "image.txt", "image/png"},
+		{"extension refines text", `{"a":1}`, "data.json", "application/json"},
+		{"plain text", "hello world", "hello", "text/plain; charset=utf-8"},
+		{"unknown binary", "\x00\x01\x02", "blob", "application/octet-stream"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			contentType, r, err := detectContentType(strings.NewReader(tt.content), tt.fileName, false)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, contentType)
+
+			content, err := ioutil.ReadAll(r)
+			require.NoError(t, err)
+			require.Equal(t, tt.content, string(content))
+		})
+	}
+}
+
+func TestClient_SendImageContentType(t *testing.T) {
+	ctx := context.Background()
+
+	var contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		contentType = header.Header.Get("Content-Type")
+
+		_, _ = w.Write([]byte(`{"file":"https://cdn/image"}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("key", "secret", WithBaseURL(srv.URL))
+	require.NoError(t, err)
+	ch := c.Channel("messaging", "general")
+
+	t.Run("detected image", func(t *testing.T) {
+		var sent, total int64
+		gif := "GIF89a" + strings.Repeat("\x00", 100)
+
+		_, err := ch.SendImage(ctx, SendFileRequest{
+			Reader:   strings.NewReader(gif),
+			FileName: "image",
+			User:     &User{ID: "user"},
+			Progress: func(s, t int64) {
+				sent, total = s, t
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "image/gif", contentType)
+		require.EqualValues(t, len(gif), sent)
+		require.EqualValues(t, len(gif), total)
+	})
+
+	t.Run("not an image", func(t *testing.T) {
+		_, err := ch.SendImage(ctx, SendFileRequest{
+			Reader:   strings.NewReader("hello world"),
+			FileName: "hello.txt",
+			User:     &User{ID: "user"},
+		})
+		require.EqualError(t, err, `content type "text/plain; charset=utf-8" is not an image`)
+	})
+
+	t.Run("text named as an image", func(t *testing.T) {
+		file, err := os.Open(filepath.Join("testdata", "helloworld.txt"))
+		require.NoError(t, err)
+		defer file.Close()
+
+		_, err = ch.SendImage(ctx, SendFileRequest{
+			Reader:   file,
+			FileName: "a.png",
+			User:     &User{ID: "user"},
+		})
+		require.EqualError(t, err, `content type "text/plain; charset=utf-8" is not an image`)
+	})
+}
@@ -1,6 +1,7 @@
 package stream_chat // nolint: golint
 
 import (
+	"context"
 	"log"
 	"testing"
 
@@ -9,62 +10,64 @@ import (
 )
 
 func TestClient_ShadowBanUser(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 	userA := randomUser(t, c)
 	userB := randomUser(t, c)
 	userC := randomUser(t, c)
 
 	ch := initChannel(t, c, userA.ID, userB.ID, userC.ID)
-	ch, err := c.CreateChannel(ch.Type, ch.ID, userA.ID, nil)
+	ch, err := c.CreateChannel(ctx, ch.Type, ch.ID, userA.ID, nil)
 	require.NoError(t, err)
 
 	// shadow ban userB globally
-	err = c.ShadowBan(userB.ID, userA.ID, nil)
+	err = c.ShadowBan(ctx, userB.ID, userA.ID, nil)
 	require.NoError(t, err)
 
 	// shadow ban userC on channel
-	err = ch.ShadowBan(userC.ID, userA.ID, nil)
+	err = ch.ShadowBan(ctx, userC.ID, userA.ID, nil)
 	require.NoError(t, err)
 
 	msg := &Message{Text: "test message"}
-	msg, err = ch.SendMessage(msg, userB.ID)
+	msg, err = ch.SendMessage(ctx, msg, userB.ID)
 	require.NoError(t, err)
 	require.Equal(t, false, msg.Shadowed)
 
-	msg, err = c.GetMessage(msg.ID)
+	msg, err = c.GetMessage(ctx, msg.ID)
 	require.NoError(t, err)
 	require.Equal(t, true, msg.Shadowed)
 
 	msg = &Message{Text: "test message"}
-	msg, err = ch.SendMessage(msg, userC.ID)
+	msg, err = ch.SendMessage(ctx, msg, userC.ID)
 	require.NoError(t, err)
 	require.Equal(t, false, msg.Shadowed)
 
-	msg, err = c.GetMessage(msg.ID)
+	msg, err = c.GetMessage(ctx, msg.ID)
 	require.NoError(t, err)
 	require.Equal(t, true, msg.Shadowed)
 
-	err = c.RemoveShadowBan(userB.ID, nil)
+	err = c.RemoveShadowBan(ctx, userB.ID, nil)
 	require.NoError(t, err)
 
 	msg = &Message{Text: "test message"}
-	msg, err = ch.SendMessage(msg, userB.ID)
+	msg, err = ch.SendMessage(ctx, msg, userB.ID)
 	require.NoError(t, err)
 	require.Equal(t, false, msg.Shadowed)
 
-	msg, err = c.GetMessage(msg.ID)
+	msg, err = c.GetMessage(ctx, msg.ID)
 	require.NoError(t, err)
 	require.Equal(t, false, msg.Shadowed)
 
-	err = ch.RemoveShadowBan(userC.ID)
+	err = ch.RemoveShadowBan(ctx, userC.ID)
 	require.NoError(t, err)
 
 	msg = &Message{Text: "test message"}
-	msg, err = ch.SendMessage(msg, userC.ID)
+	msg, err = ch.SendMessage(ctx, msg, userC.ID)
 	require.NoError(t, err)
 	require.Equal(t, false, msg.Shadowed)
 
-	msg, err = c.GetMessage(msg.ID)
+	msg, err = c.GetMessage(ctx, msg.ID)
 	require.NoError(t, err)
 	require.Equal(t, false, msg.Shadowed)
 }
@@ -84,13 +87,15 @@ func TestClient_FlagUser(t *testing.T) {
 }
 
 func TestClient_MuteUser(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 
 	user := randomUser(t, c)
-	err := c.MuteUser(randomUser(t, c).ID, user.ID, nil)
+	err := c.MuteUser(ctx, randomUser(t, c).ID, user.ID, nil)
 	require.NoError(t, err, "MuteUser should not return an error")
 
-	users, err := c.QueryUsers(&QueryOption{
+	users, err := c.QueryUsers(ctx, &QueryOption{
 		Filter: map[string]interface{}{
 			"id": map[string]string{"$eq": user.ID},
 		},
@@ -106,10 +111,10 @@ func TestClient_MuteUser(t *testing.T) {
 
 	user = randomUser(t, c)
 	// when timeout is given, expiration field should be set on mute
-	err = c.MuteUser(randomUser(t, c).ID, user.ID, map[string]interface{}{"timeout": 60})
+	err = c.MuteUser(ctx, randomUser(t, c).ID, user.ID, map[string]interface{}{"timeout": 60})
 	require.NoError(t, err, "MuteUser should not return an error")
 
-	users, err = c.QueryUsers(&QueryOption{
+	users, err = c.QueryUsers(ctx, &QueryOption{
 		Filter: map[string]interface{}{
 			"id": map[string]string{"$eq": user.ID},
 		},
@@ -125,15 +130,17 @@ func TestClient_MuteUser(t *testing.T) {
 }
 
 func TestClient_MuteUsers(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 
 	user := randomUser(t, c)
 	targetIDs := randomUsersID(t, c, 2)
 
-	err := c.MuteUsers(targetIDs, user.ID, map[string]interface{}{"timeout": 60})
+	err := c.MuteUsers(ctx, targetIDs, user.ID, map[string]interface{}{"timeout": 60})
 	require.NoError(t, err, "MuteUsers should not return an error")
 
-	users, err := c.QueryUsers(&QueryOption{
+	users, err := c.QueryUsers(ctx, &QueryOption{
 		Filter: map[string]interface{}{
 			"id": map[string]string{"$eq": user.ID},
 		},
@@ -154,35 +161,41 @@ func TestClient_UnFlagUser(t *testing.T) {
 }
 
 func TestClient_UnmuteUser(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 
 	user := randomUser(t, c)
 	mutedUser := randomUser(t, c)
-	err := c.MuteUser(mutedUser.ID, user.ID, nil)
+	err := c.MuteUser(ctx, mutedUser.ID, user.ID, nil)
 	require.NoError(t, err, "MuteUser should not return an error")
 
-	err = c.UnmuteUser(mutedUser.ID, user.ID)
+	err = c.UnmuteUser(ctx, mutedUser.ID, user.ID)
 	assert.NoError(t, err)
 }
 
 func TestClient_UnmuteUsers(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 
 	user := randomUser(t, c)
 	targetIDs := []string{randomUser(t, c).ID, randomUser(t, c).ID}
-	err := c.MuteUsers(targetIDs, user.ID, nil)
+	err := c.MuteUsers(ctx, targetIDs, user.ID, nil)
 	require.NoError(t, err, "MuteUsers should not return an error")
 
-	err = c.UnmuteUsers(targetIDs, user.ID)
+	err = c.UnmuteUsers(ctx, targetIDs, user.ID)
 	assert.NoError(t, err, "unmute users")
 }
 
 func TestClient_UpsertUsers(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 
 	user := &User{ID: randomString(10)}
 
-	resp, err := c.UpsertUsers(user)
+	resp, err := c.UpsertUsers(ctx, user)
 	require.NoError(t, err, "update users")
 
 	assert.Contains(t, resp, user.ID)
@@ -191,6 +204,8 @@ func TestClient_UpsertUsers(t *testing.T) {
 }
 
 func TestClient_PartialUpdateUsers(t *testing.T) {
+	ctx := context.Background()
+
 	c := initClient(t)
 
 	user := randomUser(t, c)
@@ -204,7 +219,7 @@ func TestClient_PartialUpdateUsers(t *testing.T) {
 		},
 	}
 
-	got, err := c.PartialUpdateUsers([]PartialUserUpdate{update})
+	got, err := c.PartialUpdateUsers(ctx, []PartialUserUpdate{update})
 	require.NoError(t, err, "partial update user")
 
 	assert.Contains(t, got, user.ID)
@@ -219,7 +234,7 @@ func TestClient_PartialUpdateUsers(t *testing.T) {
 		Unset: []string{"test.passed"},
 	}
 
-	got, err = c.PartialUpdateUsers([]PartialUserUpdate{update})
+	got, err = c.PartialUpdateUsers(ctx, []PartialUserUpdate{update})
 	require.NoError(t, err, "partial update user")
 
 	assert.Contains(t, got, user.ID)
@@ -228,9 +243,11 @@ func TestClient_PartialUpdateUsers(t *testing.T) {
 }
 
 func ExampleClient_UpsertUser() {
+	ctx := context.Background()
+
 	client, _ := NewClient("XXXX", "XXXX")
 
-	_, err := client.UpsertUser(&User{
+	_, err := client.UpsertUser(ctx, &User{
 		ID:   "tommaso",
 		Name: "Tommaso",
 		Role: "Admin",
@@ -241,31 +258,41 @@ func ExampleClient_UpsertUser() {
 }
 
 func ExampleClient_ExportUser() {
+	ctx := context.Background()
+
 	client, _ := NewClient("XXXX", "XXXX")
 
-	user, _ := client.ExportUser("userID", nil)
+	user, _ := client.ExportUser(ctx, "userID", nil)
 	log.Printf("%#v", user)
 }
 
 func ExampleClient_DeactivateUser() {
+	ctx := context.Background()
+
 	client, _ := NewClient("XXXX", "XXXX")
 
-	_ = client.DeactivateUser("userID", nil)
+	_ = client.DeactivateUser(ctx, "userID", nil)
 }
 
 func ExampleClient_ReactivateUser() {
+	ctx := context.Background()
+
 	client, _ := NewClient("XXXX", "XXXX")
 
-	_ = client.ReactivateUser("userID", nil)
+	_ = client.ReactivateUser(ctx, "userID", nil)
 }
 
 func ExampleClient_DeleteUser() {
+	ctx := context.Background()
+
 	client, _ := NewClient("XXXX", "XXXX")
 
-	_ = client.DeleteUser("userID", nil)
+	_ = client.DeleteUser(ctx, "userID", nil)
 }
 
 func ExampleClient_DeleteUser_hard() {
+	ctx := context.Background()
+
 	client, _ := NewClient("XXXX", "XXXX")
 
 	options := map[string][]string{
@@ -273,25 +300,27 @@ func ExampleClient_DeleteUser_hard() {
 		"hard_delete":           {"true"},
 	}
 
-	_ = client.DeleteUser("userID", options)
+	_ = client.DeleteUser(ctx, "userID", options)
 }
 
 func ExampleClient_BanUser() {
+	ctx := context.Background()
+
 	client, _ := NewClient("XXXX", "XXXX")
 
 	// ban a user for 60 minutes from all channel
-	_ = client.BanUser("eviluser", "modUser",
+	_ = client.BanUser(ctx, "eviluser", "modUser",
 		map[string]interface{}{"timeout": 60, "reason": "Banned for one hour"})
 
 	// ban a user from the livestream:fortnite channel
 	channel := client.Channel("livestream", "fortnite")
-	_ = channel.BanUser("eviluser", "modUser",
+	_ = channel.BanUser(ctx, "eviluser", "modUser",
 		map[string]interface{}{"reason": "Profanity is not allowed here"})
 
 	// remove ban from channel
 	channel = client.Channel("livestream", "fortnite")
-	_ = channel.UnBanUser("eviluser", nil)
+	_ = channel.UnBanUser(ctx, "eviluser", nil)
 
 	// remove global ban
-	_ = client.UnBanUser("eviluser", nil)
+	_ = client.UnBanUser(ctx, "eviluser", nil)
 }
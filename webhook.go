@@ -0,0 +1,194 @@
+package stream_chat // nolint: golint
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	webhookSignatureHeader = "X-Signature"
+
+	// maxWebhookBodySize is the largest webhook payload accepted by WebhookHandler.
+	maxWebhookBodySize = 10 << 20
+)
+
+// EventHandlerFunc handles an event received from a webhook.
+// Returning an error makes the webhook request fail, so that it is retried by Stream.
+type EventHandlerFunc func(*Event) error
+
+// WebhookHandler is an http.Handler receiving webhook requests. It verifies their
+// signature with the client secret, decodes the event and calls the handlers registered
+// for its type. Requests with an invalid signature are answered with 401 Unauthorized, and
+// the ones whose handler fails with 500 Internal Server Error.
+type WebhookHandler struct {
+	client *Client
+	store  EventStore
+	maxAge time.Duration
+
+	mu        sync.RWMutex
+	handlers  map[EventType][]EventHandlerFunc
+	unhandled EventHandlerFunc
+}
+
+// NewWebhookHandler creates a WebhookHandler verifying requests with the secret of the client.
+func (c *Client) NewWebhookHandler(options ...WebhookOption) *WebhookHandler {
+	h := &WebhookHandler{
+		client:   c,
+		handlers: make(map[EventType][]EventHandlerFunc),
+	}
+	for _, opt := range options {
+		opt(h)
+	}
+	return h
+}
+
+// On registers fn to be called for events of the given type. Several handlers can be
+// registered for the same type, they are called in order until one of them fails.
+func (h *WebhookHandler) On(eventType EventType, fn EventHandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.handlers[eventType] = append(h.handlers[eventType], fn)
+}
+
+// OnUnhandled registers fn to be called for events which have no handler registered for their type.
+func (h *WebhookHandler) OnUnhandled(fn EventHandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.unhandled = fn
+}
+
+// OnMessageNew registers fn to be called for message.new events.
+func (h *WebhookHandler) OnMessageNew(fn EventHandlerFunc) { h.On(EventMessageNew, fn) }
+
+// OnMessageUpdated registers fn to be called for message.updated events.
+func (h *WebhookHandler) OnMessageUpdated(fn EventHandlerFunc) { h.On(EventMessageUpdated, fn) }
+
+// OnMessageDeleted registers fn to be called for message.deleted events.
+func (h *WebhookHandler) OnMessageDeleted(fn EventHandlerFunc) { h.On(EventMessageDeleted, fn) }
+
+// OnMessageRead registers fn to be called for message.read events.
+func (h *WebhookHandler) OnMessageRead(fn EventHandlerFunc) { h.On(EventMessageRead, fn) }
+
+// OnReactionNew registers fn to be called for reaction.new events.
+func (h *WebhookHandler) OnReactionNew(fn EventHandlerFunc) { h.On(EventReactionNew, fn) }
+
+// OnReactionDeleted registers fn to be called for reaction.deleted events.
+func (h *WebhookHandler) OnReactionDeleted(fn EventHandlerFunc) { h.On(EventReactionDeleted, fn) }
+
+// OnMemberAdded registers fn to be called for member.added events.
+func (h *WebhookHandler) OnMemberAdded(fn EventHandlerFunc) { h.On(EventMemberAdded, fn) }
+
+// OnMemberUpdated registers fn to be called for member.updated events.
+func (h *WebhookHandler) OnMemberUpdated(fn EventHandlerFunc) { h.On(EventMemberUpdated, fn) }
+
+// OnMemberRemoved registers fn to be called for member.removed events.
+func (h *WebhookHandler) OnMemberRemoved(fn EventHandlerFunc) { h.On(EventMemberRemoved, fn) }
+
+// OnChannelCreated registers fn to be called for channel.created events.
+func (h *WebhookHandler) OnChannelCreated(fn EventHandlerFunc) { h.On(EventChannelCreated, fn) }
+
+// OnChannelUpdated registers fn to be called for channel.updated events.
+func (h *WebhookHandler) OnChannelUpdated(fn EventHandlerFunc) { h.On(EventChannelUpdated, fn) }
+
+// OnChannelDeleted registers fn to be called for channel.deleted events.
+func (h *WebhookHandler) OnChannelDeleted(fn EventHandlerFunc) { h.On(EventChannelDeleted, fn) }
+
+// OnChannelTruncated registers fn to be called for channel.truncated events.
+func (h *WebhookHandler) OnChannelTruncated(fn EventHandlerFunc) { h.On(EventChannelTruncated, fn) }
+
+// OnUserUpdated registers fn to be called for user.updated events.
+func (h *WebhookHandler) OnUserUpdated(fn EventHandlerFunc) { h.On(EventUserUpdated, fn) }
+
+// ServeHTTP implements http.Handler.
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize+1))
+	if err != nil {
+		http.Error(w, "cannot read body", http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxWebhookBodySize {
+		http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if !h.client.VerifyWebhook(body, []byte(r.Header.Get(webhookSignatureHeader))) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		http.Error(w, "invalid event", http.StatusBadRequest)
+		return
+	}
+
+	if h.stale(&event) {
+		http.Error(w, "event is too old", http.StatusBadRequest)
+		return
+	}
+
+	var key string
+	if h.store != nil {
+		key = EventKey(&event)
+	}
+	if key != "" {
+		added, err := h.store.Add(key)
+		if err != nil {
+			h.client.logf("chat-client: webhook event store failed: %v", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if !added {
+			// already handled, acknowledge it so that it is not delivered again
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+	}
+
+	if err := h.dispatch(&event); err != nil {
+		h.client.logf("chat-client: webhook handler for %s event failed: %v", event.Type, err)
+		if key != "" {
+			if err := h.store.Remove(key); err != nil {
+				h.client.logf("chat-client: webhook event store failed: %v", err)
+			}
+		}
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// dispatch calls the handlers registered for the type of the event.
+func (h *WebhookHandler) dispatch(event *Event) error {
+	h.mu.RLock()
+	handlers := h.handlers[event.Type]
+	unhandled := h.unhandled
+	h.mu.RUnlock()
+
+	if len(handlers) == 0 {
+		if unhandled == nil {
+			return nil
+		}
+		handlers = []EventHandlerFunc{unhandled}
+	}
+
+	for _, fn := range handlers {
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package stream_chat // nolint: golint
+
+import (
+	"container/list"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultEventStoreSize is the number of event keys remembered by the store created by
+// WithEventDeduplication when none is given.
+const defaultEventStoreSize = 10000
+
+// EventStore remembers the webhook events already processed by a WebhookHandler, so that
+// the ones delivered again by Stream are not handled twice. Implementations must be safe for
+// concurrent use. A shared store, for example backed by Redis, deduplicates events received
+// by several instances of an application.
+type EventStore interface {
+	// Add records the key and reports whether it was not already present.
+	Add(key string) (added bool, err error)
+	// Remove forgets the key, so that the event can be processed again.
+	Remove(key string) error
+}
+
+// LRUEventStore is an in-memory EventStore which remembers a bounded number of keys,
+// evicting the least recently added ones first.
+type LRUEventStore struct {
+	size int
+
+	mu    sync.Mutex
+	keys  map[string]*list.Element
+	order *list.List
+}
+
+// NewLRUEventStore creates a LRUEventStore remembering up to size keys.
+func NewLRUEventStore(size int) *LRUEventStore {
+	if size <= 0 {
+		size = defaultEventStoreSize
+	}
+	return &LRUEventStore{
+		size:  size,
+		keys:  make(map[string]*list.Element, size),
+		order: list.New(),
+	}
+}
+
+// Add implements EventStore.
+func (s *LRUEventStore) Add(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.keys[key]; ok {
+		s.order.MoveToFront(el)
+		return false, nil
+	}
+
+	s.keys[key] = s.order.PushFront(key)
+	if s.order.Len() > s.size {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.keys, oldest.Value.(string))
+	}
+	return true, nil
+}
+
+// Remove implements EventStore.
+func (s *LRUEventStore) Remove(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.keys[key]; ok {
+		s.order.Remove(el)
+		delete(s.keys, key)
+	}
+	return nil
+}
+
+// EventKey returns the identity of a webhook event, made of its type, its channel, the ID of
+// its message or reaction, its user, its member and its creation time. Events without creation
+// time have no identity and an empty key is returned.
+func EventKey(e *Event) string {
+	if e.CreatedAt.IsZero() {
+		return ""
+	}
+
+	var id string
+	switch {
+	case e.Reaction != nil:
+		id = e.Reaction.MessageID + "/" + e.Reaction.UserID + "/" + e.Reaction.Type
+	case e.Message != nil:
+		id = e.Message.ID
+	}
+
+	userID := e.UserID
+	if e.User != nil && e.User.ID != "" {
+		userID = e.User.ID
+	}
+	var memberID string
+	if e.Member != nil {
+		memberID = e.Member.UserID
+	}
+
+	return strings.Join([]string{
+		string(e.Type), e.CID, id, userID, memberID, strconv.FormatInt(e.CreatedAt.UnixNano(), 10),
+	}, "|")
+}
+
+// WebhookOption configures a WebhookHandler created with NewWebhookHandler.
+type WebhookOption func(*WebhookHandler)
+
+// WithEventDeduplication makes the WebhookHandler call the handlers once per event, even if
+// Stream delivers it several times. Duplicates are acknowledged without being handled.
+// Events are identified with EventKey and remembered in store; a LRUEventStore is used
+// when store is nil. Events whose handler fails are forgotten, so that their retry is handled.
+func WithEventDeduplication(store EventStore) WebhookOption {
+	return func(h *WebhookHandler) {
+		if store == nil {
+			store = NewLRUEventStore(defaultEventStoreSize)
+		}
+		h.store = store
+	}
+}
+
+// WithMaxEventAge makes the WebhookHandler reject events created more than maxAge ago with
+// 400 Bad Request, which protects against replayed requests.
+func WithMaxEventAge(maxAge time.Duration) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.maxAge = maxAge
+	}
+}
+
+// stale reports whether the event is older than the max age of the handler.
+func (h *WebhookHandler) stale(e *Event) bool {
+	return h.maxAge > 0 && !e.CreatedAt.IsZero() && time.Since(e.CreatedAt) > h.maxAge
+}
@@ -0,0 +1,146 @@
+package stream_chat // nolint: golint
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUEventStore(t *testing.T) {
+	s := NewLRUEventStore(2)
+
+	for _, key := range []string{"a", "b"} {
+		added, err := s.Add(key)
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+
+	added, err := s.Add("a")
+	require.NoError(t, err)
+	require.False(t, added)
+
+	// "b" is the least recently used key and gets evicted
+	added, err = s.Add("c")
+	require.NoError(t, err)
+	require.True(t, added)
+	added, err = s.Add("b")
+	require.NoError(t, err)
+	require.True(t, added)
+
+	require.NoError(t, s.Remove("b"))
+	added, err = s.Add("b")
+	require.NoError(t, err)
+	require.True(t, added)
+}
+
+func TestEventKey(t *testing.T) {
+	createdAt := time.Date(2021, 11, 17, 10, 0, 0, 0, time.UTC)
+
+	msg := &Event{Type: EventMessageNew, Message: &Message{ID: "msg-1"}, CreatedAt: createdAt}
+	reaction := &Event{
+		Type:      EventReactionNew,
+		Message:   &Message{ID: "msg-1"},
+		Reaction:  &Reaction{MessageID: "msg-1", UserID: "user", Type: "like"},
+		CreatedAt: createdAt,
+	}
+
+	require.Equal(t, fmt.Sprintf("message.new||msg-1|||%d", createdAt.UnixNano()), EventKey(msg))
+	require.NotEqual(t, EventKey(msg), EventKey(reaction))
+	require.Empty(t, EventKey(&Event{Type: EventMessageNew, Message: &Message{ID: "msg-1"}}))
+
+	// events without message are told apart by their channel, user and member
+	added := func(cid, userID string) *Event {
+		return &Event{
+			Type: EventMemberAdded, CID: cid, User: &User{ID: "admin"},
+			Member: &ChannelMember{UserID: userID}, CreatedAt: createdAt,
+		}
+	}
+	require.NotEqual(t, EventKey(added("messaging:a", "tommaso")), EventKey(added("messaging:b", "tommaso")))
+	require.NotEqual(t, EventKey(added("messaging:a", "tommaso")), EventKey(added("messaging:a", "thierry")))
+	require.NotEqual(t,
+		EventKey(&Event{Type: EventUserUpdated, User: &User{ID: "tommaso"}, CreatedAt: createdAt}),
+		EventKey(&Event{Type: EventUserUpdated, UserID: "thierry", CreatedAt: createdAt}))
+}
+
+func TestWebhookHandler_Deduplication(t *testing.T) {
+	c, err := NewClient("key", "secret")
+	require.NoError(t, err)
+
+	var calls int
+	fail := true
+	h := c.NewWebhookHandler(WithEventDeduplication(nil))
+	h.OnMessageNew(func(e *Event) error {
+		calls++
+		if fail {
+			fail = false
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	body := fmt.Sprintf(`{"type":"message.new","message":{"id":"msg-1"},"created_at":%q}`,
+		time.Now().UTC().Format(time.RFC3339Nano))
+	send := func() int {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newWebhookRequest(body, signWebhook("secret", body)))
+		return w.Code
+	}
+
+	// failed events are handled again when retried, successful ones only once
+	require.Equal(t, http.StatusInternalServerError, send())
+	require.Equal(t, http.StatusOK, send())
+	require.Equal(t, http.StatusOK, send())
+	require.Equal(t, 2, calls)
+}
+
+func TestWebhookHandler_DeduplicationWithoutMessage(t *testing.T) {
+	c, err := NewClient("key", "secret")
+	require.NoError(t, err)
+
+	var cids []string
+	h := c.NewWebhookHandler(WithEventDeduplication(nil))
+	h.OnMemberAdded(func(e *Event) error {
+		cids = append(cids, e.CID)
+		return nil
+	})
+
+	createdAt := time.Now().UTC().Format(time.RFC3339Nano)
+	for _, cid := range []string{"messaging:a", "messaging:b", "messaging:a"} {
+		body := fmt.Sprintf(`{"type":"member.added","cid":%q,"member":{"user_id":"tommaso"},"created_at":%q}`, cid, createdAt)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newWebhookRequest(body, signWebhook("secret", body)))
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+	require.Equal(t, []string{"messaging:a", "messaging:b"}, cids, "only the redelivery is a duplicate")
+}
+
+func TestWebhookHandler_MaxEventAge(t *testing.T) {
+	c, err := NewClient("key", "secret")
+	require.NoError(t, err)
+
+	var calls int
+	h := c.NewWebhookHandler(WithMaxEventAge(time.Minute))
+	h.OnMessageNew(func(e *Event) error {
+		calls++
+		return nil
+	})
+
+	for _, tt := range []struct {
+		createdAt time.Time
+		want      int
+	}{
+		{time.Now(), http.StatusOK},
+		{time.Now().Add(-time.Hour), http.StatusBadRequest},
+	} {
+		body := fmt.Sprintf(`{"type":"message.new","created_at":%q}`, tt.createdAt.UTC().Format(time.RFC3339Nano))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newWebhookRequest(body, signWebhook("secret", body)))
+		require.Equal(t, tt.want, w.Code)
+	}
+	require.Equal(t, 1, calls)
+}
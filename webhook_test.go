@@ -0,0 +1,116 @@
+package stream_chat // nolint: golint
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func signWebhook(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newWebhookRequest(body, signature string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	r.Header.Set("X-Signature", signature)
+	return r
+}
+
+func TestWebhookHandler(t *testing.T) {
+	c, err := NewClient("key", "secret")
+	require.NoError(t, err)
+
+	var received []*Event
+	h := c.NewWebhookHandler()
+	h.OnMessageNew(func(e *Event) error {
+		received = append(received, e)
+		return nil
+	})
+	h.OnReactionNew(func(e *Event) error {
+		return errors.New("boom")
+	})
+
+	t.Run("dispatches event", func(t *testing.T) {
+		body := `{"type":"message.new","cid":"messaging:general","message":{"id":"msg-1","text":"hi"}}`
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newWebhookRequest(body, signWebhook("secret", body)))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Len(t, received, 1)
+		require.Equal(t, EventMessageNew, received[0].Type)
+		require.Equal(t, "msg-1", received[0].Message.ID)
+	})
+
+	t.Run("ignores events without handler", func(t *testing.T) {
+		body := `{"type":"user.updated"}`
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newWebhookRequest(body, signWebhook("secret", body)))
+
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("rejects invalid signature", func(t *testing.T) {
+		body := `{"type":"message.new","message":{"id":"msg-2"}}`
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newWebhookRequest(body, signWebhook("other", body)))
+
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+		require.Len(t, received, 1)
+	})
+
+	t.Run("rejects invalid body", func(t *testing.T) {
+		body := `not json`
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newWebhookRequest(body, signWebhook("secret", body)))
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("reports handler failure", func(t *testing.T) {
+		body := `{"type":"reaction.new"}`
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newWebhookRequest(body, signWebhook("secret", body)))
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("rejects other methods", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/webhook", nil))
+
+		require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
+
+func TestWebhookHandler_OnUnhandled(t *testing.T) {
+	c, err := NewClient("key", "secret")
+	require.NoError(t, err)
+
+	var types []EventType
+	h := c.NewWebhookHandler()
+	h.On(EventChannelCreated, func(e *Event) error {
+		types = append(types, "handled")
+		return nil
+	})
+	h.OnUnhandled(func(e *Event) error {
+		types = append(types, e.Type)
+		return nil
+	})
+
+	for _, body := range []string{`{"type":"channel.created"}`, `{"type":"health.check"}`} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newWebhookRequest(body, signWebhook("secret", body)))
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+
+	require.Equal(t, []EventType{"handled", EventHealthCheck}, types)
+}